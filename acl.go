@@ -0,0 +1,44 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"regexp"
+)
+
+// aclUserRegexp matches user names screen's access control commands take one of. Commas would make a list.
+var aclUserRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*\$?$`)
+
+// aclAdminCommands are the commands a read-only user may not run, since they'd let them change their own access.
+const aclAdminCommands = "acladd,aclchg,acldel,aclgrp,aclumask,multiuser,su,writelock"
+
+// GrantReadOnly lets another user attach to the screen ("screen -x <owner>/<name>") and watch every window, without
+// being able to type into them or change who may access the screen. It turns multiuser mode on, which needs screen to
+// be installed setuid root for other users to reach the socket. See RevokeAccess.
+func (s Screen) GrantReadOnly(user string) error {
+	if err := validateACLUser(user); err != nil {
+		return err
+	}
+	return s.Batch(func(b *Batch) {
+		b.Command("multiuser", "on")
+		b.Command("acladd", user)
+		b.Command("aclchg", user, "-w", "#")
+		b.Command("aclchg", user, "-x", aclAdminCommands)
+	})
+}
+
+// RevokeAccess removes a user from the screen's access control list, and detaches them if they're attached.
+func (s Screen) RevokeAccess(user string) error {
+	if err := validateACLUser(user); err != nil {
+		return err
+	}
+	return s.builtinTemplateArgs("acldel", user)
+}
+
+// validateACLUser rejects names that aren't a single user.
+func validateACLUser(user string) error {
+	if !aclUserRegexp.MatchString(user) {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid user name " + user)}
+	}
+	return nil
+}
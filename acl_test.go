@@ -0,0 +1,30 @@
+package screen
+
+import (
+	"testing"
+)
+
+func TestGrantReadOnly(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b -X "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b": fakeList,
+		screen + "eval multiuser 'on' acladd 'alice' aclchg 'alice' '-w' '#' aclchg 'alice' '-x' '" + aclAdminCommands + "'": "",
+		screen + "acldel alice": "",
+	}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.GrantReadOnly("alice"); err != nil {
+		t.Error(err)
+	}
+	if err = s.RevokeAccess("alice"); err != nil {
+		t.Error(err)
+	}
+	for _, user := range []string{"", "alice,bob", "alice bob", "'"} {
+		if err = s.GrantReadOnly(user); err == nil {
+			t.Errorf("%q: granted", user)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Alerter gets an event in front of a human, i.e. "the installer in session X is asking a question".
+type Alerter interface {
+	Alert(ctx context.Context, e Event) error
+}
+
+// AlertOn returns a function for a Manager's OnEvent, which passes events of the given types (all if none are given)
+// to the alerter in the background. Errors are passed to onError, if it isn't nil.
+func AlertOn(a Alerter, onError func(e Event, err error), types ...EventType) func(e Event) {
+	return func(e Event) {
+		if len(types) > 0 && !containsEventType(types, e.Type) {
+			return
+		}
+		go func() {
+			if err := a.Alert(context.Background(), e); err != nil && onError != nil {
+				onError(e, err)
+			}
+		}()
+	}
+}
+
+func containsEventType(types []EventType, t EventType) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the event in a line, for humans.
+func (e Event) String() string {
+	name := e.Screen.Name
+	if e.Screen.Process != nil {
+		name += " (PID " + strconv.Itoa(e.Screen.Process.Pid) + ")"
+	}
+
+	switch e.Type {
+	case EventCreated:
+		return "screen " + name + " was created"
+	case EventDied:
+		return "screen " + name + " is gone"
+	case EventAttached:
+		return "screen " + name + " was attached"
+	case EventDetached:
+		return "screen " + name + " was detached"
+	case EventHung:
+		return "screen " + name + " is not responding"
+	case EventMatched:
+		return fmt.Sprintf("screen %s printed %q", name, e.Text)
+	case EventBell:
+		if e.Window != nil {
+			return fmt.Sprintf("screen %s rang the bell in window %d", name, *e.Window)
+		}
+	}
+	return "screen " + name + ": " + string(e.Type)
+}
+
+// Alert delivers the event like Notify, so a Webhook can be used as an Alerter.
+func (w *Webhook) Alert(ctx context.Context, e Event) error {
+	return w.Notify(ctx, e)
+}
+
+// SlackAlerter posts events to a Slack incoming webhook.
+type SlackAlerter struct {
+	WebhookURL string
+	Client     *http.Client // http.DefaultClient if nil
+}
+
+// Alert posts the event's description to the Slack channel.
+func (a SlackAlerter) Alert(ctx context.Context, e Event) error {
+	body, err := json.Marshal(map[string]string{"text": e.String()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return errors.New("slack returned " + strconv.Itoa(res.StatusCode))
+	}
+	return nil
+}
+
+// EmailAlerter mails events through an SMTP server.
+type EmailAlerter struct {
+	Addr string    // Of the SMTP server, "host:port"
+	Auth smtp.Auth // nil for none
+	From string
+	To   []string
+}
+
+// Alert mails the event, with its description as the subject.
+func (a EmailAlerter) Alert(ctx context.Context, e Event) error {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(e.String())
+	details, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	msg := "From: " + a.From + "\r\nTo: " + strings.Join(a.To, ", ") + "\r\nSubject: " + subject +
+		"\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n" + string(details) + "\r\n"
+	return smtp.SendMail(a.Addr, a.Auth, a.From, a.To, []byte(msg))
+}
+
+// ExecAlerter runs a command on this machine for every event, with the event as JSON on its stdin, and in the
+// environment as SCREEN_EVENT (its type), SCREEN_NAME, SCREEN_PID and SCREEN_MESSAGE (its description).
+type ExecAlerter struct {
+	Command string
+	Args    []string
+}
+
+// Alert runs the command, and waits for it to finish.
+func (a ExecAlerter) Alert(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, a.Command, a.Args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "SCREEN_EVENT="+string(e.Type), "SCREEN_NAME="+e.Screen.Name,
+		"SCREEN_MESSAGE="+e.String())
+	if e.Screen.Process != nil {
+		cmd.Env = append(cmd.Env, "SCREEN_PID="+strconv.Itoa(e.Screen.Process.Pid))
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return &CommandError{Command: cmd.Args, Stdout: out, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package screen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testEvent = Event{Type: EventMatched, Screen: Screen{Name: "build", Process: &os.Process{Pid: 7}}, Text: "Continue? [y/N]"}
+
+func TestEventString(t *testing.T) {
+	if got, want := testEvent.String(), `screen build (PID 7) printed "Continue? [y/N]"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSlackAlerter(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := (SlackAlerter{WebhookURL: srv.URL}).Alert(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	if got["text"] != testEvent.String() {
+		t.Errorf("got %v", got)
+	}
+
+	srv.Config.Handler = http.NotFoundHandler()
+	if err := (SlackAlerter{WebhookURL: srv.URL}).Alert(context.Background(), testEvent); err == nil {
+		t.Error("expected an error for a 404")
+	}
+}
+
+func TestExecAlerter(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	a := ExecAlerter{Command: "sh", Args: []string{"-c", `{ echo "$SCREEN_EVENT $SCREEN_NAME $SCREEN_PID"; cat; } > "$0"`, out}}
+	if err := a.Alert(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitN(string(b), "\n", 2)
+	if lines[0] != "matched build 7" || !strings.Contains(lines[1], `"text":"Continue? [y/N]"`) {
+		t.Errorf("got %q", b)
+	}
+
+	if err := (ExecAlerter{Command: "false"}).Alert(context.Background(), testEvent); err == nil {
+		t.Error("expected an error from a failing command")
+	}
+}
+
+type alertFunc func(ctx context.Context, e Event) error
+
+func (f alertFunc) Alert(ctx context.Context, e Event) error { return f(ctx, e) }
+
+func TestAlertOn(t *testing.T) {
+	got := make(chan EventType, 2)
+	fn := AlertOn(alertFunc(func(ctx context.Context, e Event) error {
+		got <- e.Type
+		return nil
+	}), nil, EventMatched, EventHung)
+
+	fn(Event{Type: EventCreated})
+	fn(Event{Type: EventHung})
+	if typ := <-got; typ != EventHung {
+		t.Errorf("got %s", typ)
+	}
+	select {
+	case typ := <-got:
+		t.Errorf("unexpected %s", typ)
+	default:
+	}
+}
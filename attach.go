@@ -0,0 +1,171 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ErrReadOnly is returned when writing to an attachment made with Observe.
+var ErrReadOnly = errors.New("attachment is read-only")
+
+// Attachment is a terminal attached to a screen, as if a user had run "screen -r" in it. Reading returns what the
+// terminal displays (escape sequences included), writing types into it.
+type Attachment struct {
+	pty  *os.File
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error // Exit error of the screen client, valid once done is closed
+
+	readOnly bool
+
+	closeOnce sync.Once
+}
+
+// Attach attaches to the screen through a new pseudo terminal, sized 80x24 until Resize or FollowSize is called.
+// The screen is detached again when ctx is done, or Close is called.
+func (s Screen) Attach(ctx context.Context) (*Attachment, error) {
+	return s.attach(ctx, "-r")
+}
+
+// AttachShared attaches to the screen without detaching anyone else, like "screen -x". Every attached display sees
+// the same session, and can type into it. See Observe and GrantReadOnly for read-only viewers.
+func (s Screen) AttachShared(ctx context.Context) (*Attachment, error) {
+	return s.attach(ctx, "-x")
+}
+
+// TakeOver attaches to the screen after power-detaching every other display, like "screen -D -R", so nobody else is
+// typing into it while automation is. Anyone detached this way is also logged out of the terminal they attached from.
+func (s Screen) TakeOver(ctx context.Context) (*Attachment, error) {
+	return s.attach(ctx, "-D", "-R")
+}
+
+// Observe attaches like AttachShared, but the returned attachment is read-only: Write fails with ErrReadOnly.
+//
+// This is only enforced on the Go side. The attachment runs as the screen's owner, and screen's ACLs work per user,
+// so screen itself would still take input from it, and other displays of the owner (like a "screen -x" in another
+// terminal) can still type. To make another user's viewers read-only in screen itself, use GrantReadOnly.
+func (s Screen) Observe(ctx context.Context) (*Attachment, error) {
+	a, err := s.attach(ctx, "-x")
+	if err != nil {
+		return nil, err
+	}
+	a.readOnly = true
+	return a, nil
+}
+
+func (s Screen) attach(ctx context.Context, flags ...string) (*Attachment, error) {
+	if err := s.verify(); err != nil {
+		return nil, err
+	}
+
+	// Not bound to ctx, which would kill the client instead of letting it detach
+	cmd, err := s.m().ttyCommand(context.Background(), screenExec, append(flags, s.target())...)
+	if err != nil {
+		return nil, err
+	}
+	if _, isSet := os.LookupEnv("TERM"); !isSet {
+		cmd.Env = append(os.Environ(), "TERM=xterm")
+	}
+
+	pty, err := startPTY(cmd, 80, 24)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{pty: pty, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		a.err = cmd.Wait()
+		close(a.done)
+	}()
+
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	go func() {
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			a.Close()
+		case <-a.done:
+		}
+	}()
+
+	return a, nil
+}
+
+// Read reads the terminal's output.
+func (a *Attachment) Read(p []byte) (int, error) {
+	return a.pty.Read(p)
+}
+
+// Write types into the terminal.
+func (a *Attachment) Write(p []byte) (int, error) {
+	if a.readOnly {
+		return 0, ErrReadOnly
+	}
+	return a.pty.Write(p)
+}
+
+// Resize changes the size of the attached terminal. Screen redraws the session to fit it.
+func (a *Attachment) Resize(cols, rows uint16) error {
+	return setSize(a.pty, cols, rows)
+}
+
+// FollowSize resizes the attachment to match term (usually os.Stdin), and keeps doing so every time the process
+// receives SIGWINCH, until the attachment ends.
+func (a *Attachment) FollowSize(term *os.File) error {
+	cols, rows, err := getSize(term)
+	if err != nil {
+		return err
+	}
+	if err = a.Resize(cols, rows); err != nil {
+		return err
+	}
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	go func() {
+		defer signal.Stop(resized)
+		for {
+			select {
+			case <-resized:
+				if cols, rows, err := getSize(term); err == nil {
+					a.Resize(cols, rows)
+				}
+			case <-a.done:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Done returns a channel that's closed once the attachment has ended.
+func (a *Attachment) Done() <-chan struct{} {
+	return a.done
+}
+
+// Wait blocks until the attachment ends, and returns how the screen client exited.
+func (a *Attachment) Wait() error {
+	<-a.done
+	return a.err
+}
+
+// Close detaches from the screen, leaving it running.
+func (a *Attachment) Close() error {
+	a.closeOnce.Do(func() {
+		// A hung up screen client detaches
+		a.cmd.Process.Signal(syscall.SIGHUP)
+		a.pty.Close()
+	})
+	<-a.done
+	return nil
+}
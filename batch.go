@@ -0,0 +1,97 @@
+package screen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Batch collects screen commands so they can be sent to a screen all at once, see Screen.Batch.
+type Batch struct {
+	commands []string
+}
+
+// Batch calls fn to collect commands, then runs all of them in a single "eval", instead of starting one screen
+// process per command. Commands run in the order they were added.
+func (s Screen) Batch(fn func(b *Batch)) error {
+	b := new(Batch)
+	fn(b)
+	if len(b.commands) == 0 {
+		return nil
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if err := s.verify(); err != nil {
+		return err
+	}
+
+	params := append([]string{"-S", s.target(), "-X", "eval"}, b.commands...)
+	if _, err := s.m().combined(screenExec, params...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command adds any screen command, see "man screen". Arguments are quoted for you.
+func (b *Batch) Command(command string, args ...string) *Batch {
+	line := command
+	for _, arg := range args {
+		line += " " + quoteArg(arg)
+	}
+	b.commands = append(b.commands, line)
+	return b
+}
+
+// Line adds a raw screenrc line, which isn't quoted in any way. Blank lines and comments are skipped.
+func (b *Batch) Line(line string) *Batch {
+	if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return b
+	}
+	b.commands = append(b.commands, line)
+	return b
+}
+
+// SetTitle adds setting the title of the current window.
+func (b *Batch) SetTitle(title string) *Batch {
+	return b.Command("title", title)
+}
+
+// Stuff adds pasting text into the screen's stdin, see Screen.Stuff.
+func (b *Batch) Stuff(commands ...string) *Batch {
+	return b.Command("stuff", strings.Join(commands, " "))
+}
+
+// Chdir adds changing the directory new windows start in, see Screen.Chdir. Unlike Screen.Chdir, the path isn't checked first.
+func (b *Batch) Chdir(path string) *Batch {
+	return b.Command("chdir", path)
+}
+
+// Log adds enabling logging to path, or disabling it if path is empty, see Screen.Log. Unlike Screen.Log, the file
+// is always appended to.
+func (b *Batch) Log(path string, flushInterval uint) *Batch {
+	if path == "" {
+		return b.Command("log", "off")
+	}
+
+	b.Command("logfile", path)
+	b.Command("logfile", "flush", strconv.Itoa(int(flushInterval)))
+	return b.Command("log", "on")
+}
+
+// Clear adds erasing the screen's scrollback buffer.
+func (b *Batch) Clear() *Batch {
+	return b.Command("clear")
+}
+
+// quoteArg quotes an argument for screen's command parser. Single quotes keep everything literal, so they're used
+// unless the argument contains one itself.
+func quoteArg(arg string) string {
+	if !strings.Contains(arg, "'") {
+		return "'" + arg + "'"
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(arg) + `"`
+}
@@ -0,0 +1,32 @@
+package screen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchCommands(t *testing.T) {
+	b := new(Batch)
+	b.SetTitle("build 42").Log("/tmp/build.log", 1).Stuff("echo", "it's $HOME\n")
+
+	want := []string{
+		"title 'build 42'",
+		"logfile '/tmp/build.log'",
+		"logfile 'flush' '1'",
+		"log 'on'",
+		`stuff "echo it's \$HOME` + "\n" + `"`,
+	}
+	if !reflect.DeepEqual(b.commands, want) {
+		t.Errorf("got %q, want %q", b.commands, want)
+	}
+}
+
+func TestBatchLine(t *testing.T) {
+	b := new(Batch)
+	b.Line("# comment").Line("").Line(`defscrollback 5000`)
+
+	want := []string{"defscrollback 5000"}
+	if !reflect.DeepEqual(b.commands, want) {
+		t.Errorf("got %q, want %q", b.commands, want)
+	}
+}
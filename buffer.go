@@ -0,0 +1,60 @@
+package screen
+
+// SetBufferFile changes the screen's exchange file, used by ReadBuf and WriteBuf when they're given no path.
+func (s Screen) SetBufferFile(path string) error {
+	return s.builtinTemplateArgs("bufferfile", path)
+}
+
+// ReadBuf loads the screen's paste buffer from a file on the screen's host. An empty path means the exchange file.
+func (s Screen) ReadBuf(path string) error {
+	if path == "" {
+		return s.builtinTemplate("readbuf")
+	}
+	return s.builtinTemplateArgs("readbuf", path)
+}
+
+// WriteBuf saves the screen's paste buffer to a file on the screen's host. An empty path means the exchange file.
+func (s Screen) WriteBuf(path string) error {
+	if path == "" {
+		return s.builtinTemplate("writebuf")
+	}
+	return s.builtinTemplateArgs("writebuf", path)
+}
+
+// Paste pastes the screen's paste buffer into its current window, as if it was typed.
+func (s Screen) Paste() error {
+	return s.builtinTemplateArgs("paste", ".")
+}
+
+// CopyToHost returns the contents of the screen's paste buffer.
+func (s Screen) CopyToHost() (string, error) {
+	name, err := s.m().tempFile()
+	if err != nil {
+		return "", err
+	}
+	defer s.m().remove(name)
+
+	if err = s.WriteBuf(name); err != nil {
+		return "", err
+	}
+	b, err := s.m().readFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// PasteFromHost puts text into the screen's paste buffer. Call Paste to type it into the current window.
+func (s Screen) PasteFromHost(text string) error {
+	name, err := s.m().tempFile()
+	if err != nil {
+		return err
+	}
+	defer s.m().remove(name)
+
+	if err = s.m().writeFile(name, []byte(text)); err != nil {
+		return err
+	}
+	return s.ReadBuf(name)
+}
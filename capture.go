@@ -0,0 +1,124 @@
+package screen
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Capture is a real-time stream of everything a screen outputs. The screen logs into a named pipe in the Manager's
+// spool directory, which we read continuously, so the output never touches the disk.
+type Capture struct {
+	s    Screen
+	r    io.ReadCloser
+	fifo string
+	mt   *meter
+	cmd  *exec.Cmd // Process reading the pipe on hosts that aren't local
+
+	unhold func() // Unregisters the capture from the Manager's background work
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Capture starts streaming the screen's output. This takes over the screen's logging, so don't call Log until the
+// capture is closed.
+func (s Screen) Capture() (*Capture, error) {
+	fifo, err := s.m().fifo()
+	if err != nil {
+		return nil, err
+	}
+	c := &Capture{s: s, fifo: fifo, mt: s.m().meter(s.Name)}
+
+	// The reader has to exist before screen opens the pipe, otherwise screen blocks until one shows up
+	if s.m().isLocal() {
+		// Opening read-write doesn't block, and means we never see EOF if screen reopens the log
+		c.r, err = os.OpenFile(c.fifo, os.O_RDWR, 0)
+	} else {
+		if c.cmd, err = s.m().command(context.Background(), "cat", c.fifo); err == nil {
+			if c.r, err = c.cmd.StdoutPipe(); err == nil {
+				err = c.cmd.Start()
+			}
+		}
+	}
+	if err != nil {
+		s.m().remove(c.fifo)
+		return nil, err
+	}
+
+	if err = s.log(c.fifo, true, 1); err != nil {
+		c.release()
+		return nil, err
+	}
+
+	if c.unhold, err = s.m().hold(s.Name, func() { c.Close() }); err != nil {
+		c.Close()
+		return nil, err
+	}
+	s.m().captures.Store(s.Name, c.fifo)
+
+	return c, nil
+}
+
+// LogTo writes everything the screen outputs to w, until ctx is done or writing fails. Like Capture, this takes over
+// the screen's logging while it runs. It returns ctx's error, or the one from w.
+func (s Screen) LogTo(ctx context.Context, w io.Writer) error {
+	c, err := s.Capture()
+	if err != nil {
+		return err
+	}
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, c)
+		copied <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+		c.Close()
+		<-copied // Unblocked by Close
+	case err = <-copied:
+		c.Close()
+	}
+
+	return err
+}
+
+// Read reads the screen's output as it happens.
+func (c *Capture) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.mt.add(time.Now(), c.s.m().throughputInterval(), p[:n])
+	}
+	return n, err
+}
+
+// Close stops the capture, turning the screen's logging back off.
+func (c *Capture) Close() error {
+	c.closeOnce.Do(func() {
+		if fifo, _ := c.s.m().captures.Load(c.s.Name); fifo == c.fifo {
+			c.s.m().captures.Delete(c.s.Name)
+		}
+		c.closeErr = c.s.log("", false, 10)
+		c.release()
+		if c.unhold != nil {
+			c.unhold()
+		}
+	})
+	return c.closeErr
+}
+
+// release stops reading the pipe, and removes it.
+func (c *Capture) release() {
+	c.r.Close()
+	if c.cmd != nil {
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+	}
+	c.s.m().remove(c.fifo)
+}
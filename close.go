@@ -0,0 +1,145 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+)
+
+// ErrClosed is returned when starting background work (a capture, watcher, attachment or pipe) on a closed Manager,
+// or doing anything that needs its spool directory.
+var ErrClosed = errors.New("manager is closed")
+
+// resources is the background work of a Manager, which Close stops.
+type resources struct {
+	mutex  sync.Mutex
+	closed bool
+	stops  map[*resource]struct{}
+}
+
+// resource is one piece of background work.
+type resource struct {
+	screen string // Name of the screen it belongs to, empty for the Manager itself
+	stop   func()
+}
+
+// hold registers background work, which stop is called for when the Manager or the screen is closed. release must be
+// called once the work is done.
+func (m *Manager) hold(screen string, stop func()) (release func(), err error) {
+	m.resources.mutex.Lock()
+	defer m.resources.mutex.Unlock()
+
+	if m.resources.closed {
+		return nil, ErrClosed
+	}
+	if m.resources.stops == nil {
+		m.resources.stops = map[*resource]struct{}{}
+	}
+	r := &resource{screen: screen, stop: stop}
+	m.resources.stops[r] = struct{}{}
+
+	return func() {
+		m.resources.mutex.Lock()
+		delete(m.resources.stops, r)
+		m.resources.mutex.Unlock()
+	}, nil
+}
+
+// bind returns a context that's also canceled when the Manager or the screen is closed. cancel must be called once
+// it's no longer needed.
+func (m *Manager) bind(ctx context.Context, screen string) (context.Context, context.CancelFunc, error) {
+	ctx, cancelCtx := context.WithCancel(ctx)
+	release, err := m.hold(screen, cancelCtx)
+	if err != nil {
+		cancelCtx()
+		return nil, nil, err
+	}
+	return ctx, func() {
+		release()
+		cancelCtx()
+	}, nil
+}
+
+// stop stops the background work of a screen, or all of it if all is set.
+func (m *Manager) stop(screen string, all bool) {
+	var stops []func()
+	m.resources.mutex.Lock()
+	for r := range m.resources.stops {
+		if all || r.screen == screen {
+			stops = append(stops, r.stop)
+			delete(m.resources.stops, r)
+		}
+	}
+	m.resources.mutex.Unlock()
+
+	// Outside the lock, since stopping usually releases
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+// Close stops everything the Manager runs in the background for its screens: captures (and everything built on them,
+// like WatchPatterns and Drivers), WatchSessions, Watchdogs, snapshots, attachments and pipes. Logfiles tracked by
+// Log are finished, so they're compressed and reported as configured, and the Manager's spool directory is removed.
+// The screens themselves keep running. Background work, and anything needing the spool directory (like hardcopies),
+// can't be started on the Manager afterwards.
+func (m *Manager) Close() error {
+	m.resources.mutex.Lock()
+	m.resources.closed = true
+	m.resources.mutex.Unlock()
+	m.stop("", true)
+
+	var err error
+	m.logs.Range(func(name, _ interface{}) bool {
+		s := Screen{Name: name.(string), Mutex: m.mutex(name.(string)), manager: m}
+		if e := s.closeLog(); e != nil && err == nil {
+			err = e
+		}
+		return true
+	})
+
+	m.spoolOnce.Do(func() { m.spoolErr = ErrClosed }) // Makes sure nothing creates it from here on
+	if m.spool != "" {
+		if e := m.removeAll(m.spool); e != nil && err == nil {
+			err = e
+		}
+		m.spool, m.spoolErr = "", ErrClosed
+	}
+	return err
+}
+
+// Close stops everything running in the background for the screen, like Manager.Close, and finishes its logfile if
+// Log is tracking one. The screen itself keeps running, see Quit and Kill for ending it.
+func (s Screen) Close() error {
+	s.m().stop(s.Name, false)
+	s.m().meters.Delete(s.Name)
+	s.m().queries.invalidate(s.target())
+	return s.closeLog()
+}
+
+// closeLog switches off the logfile Log is tracking for the screen, and finishes it. If the screen is already gone,
+// the file is finished anyway.
+func (s Screen) closeLog() error {
+	path, ok := s.m().logs.Load(s.Name)
+	if !ok {
+		return nil
+	}
+
+	err := s.Log("", false, 10)
+	if err != nil && !s.isOnline() {
+		s.m().logs.Delete(s.Name)
+		return s.m().finishLog(s, path.(string))
+	}
+	return err
+}
+
+// removeAll deletes a directory and everything in it from the Manager's host.
+func (m *Manager) removeAll(path string) error {
+	if m.isLocal() {
+		return os.RemoveAll(path)
+	}
+
+	_, err := m.combined("rm", "-rf", path)
+	return err
+}
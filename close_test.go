@@ -0,0 +1,97 @@
+package screen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManagerCloseStopsWatchers(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": fakeList}})
+
+	done := make(chan error, 1)
+	go func() { done <- m.WatchSessions(context.Background(), time.Hour) }()
+	time.Sleep(time.Millisecond * 50)
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WatchSessions kept running")
+	}
+
+	if err := m.WatchSessions(context.Background(), time.Hour); err != ErrClosed {
+		t.Errorf("got %v after Close, want ErrClosed", err)
+	}
+}
+
+func TestScreenCloseStopsOnlyItsOwn(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{})
+	var stopped []string
+	for _, name := range []string{"a", "b", ""} {
+		name := name
+		if _, err := m.hold(name, func() { stopped = append(stopped, name) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := (Screen{Name: "a", manager: m}).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(stopped) != 1 || stopped[0] != "a" {
+		t.Fatalf("stopped %q", stopped)
+	}
+
+	m.Close()
+	if len(stopped) != 3 {
+		t.Errorf("stopped %q", stopped)
+	}
+}
+
+func TestManagerCloseFinishesLogs(t *testing.T) {
+	m := NewManager()
+	m.CompressLogs = true
+	var finished string
+	m.OnLogFinished = func(s Screen, path string) { finished = path }
+
+	logfile := filepath.Join(t.TempDir(), "gone.log")
+	if err := os.WriteFile(logfile, []byte("bye\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m.logs.Store("go-gnu-screen-gone", logfile)
+
+	spool, err := m.spoolDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if finished != logfile+".gz" {
+		t.Errorf("finished %q", finished)
+	}
+	if _, err = os.Stat(spool); !os.IsNotExist(err) {
+		t.Errorf("spool directory wasn't removed: %v", err)
+	}
+	if _, err = m.tempFile(); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestManagerCloseWithoutSpool(t *testing.T) {
+	m := NewManager()
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.tempFile(); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+// fanOutReport is a FanOutResult as printed by --format json.
+type fanOutReport struct {
+	Screen screen.Screen `json:"screen"`
+	OK     bool          `json:"ok"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// stuff types text into every screen matching a pattern.
+func stuff(args []string) error {
+	format, args, err := parseFlags("stuff", args, formatTable)
+	if err != nil {
+		return err
+	}
+
+	// Everything after "--" is text, so it may start with a dash
+	if len(args) < 3 || args[1] != "--" {
+		return errors.New("usage: goscreen stuff [--format json|table|names] <pattern> -- <text>...")
+	}
+	text := unescape(strings.Join(args[2:], " "))
+
+	res, err := screen.FanOut(args[0], func(s screen.Screen) error { return s.Stuff(text) })
+	if err != nil {
+		return err
+	}
+	return printFanOut(res, format)
+}
+
+// printFanOut prints what a FanOut did to each screen. It returns errFailed if any screen failed.
+func printFanOut(res []screen.FanOutResult, format string) error {
+	reports := make([]fanOutReport, len(res))
+	out := output{JSON: reports, Header: []string{"NAME", "PID", "RESULT"}}
+	failed := false
+	for i, r := range res {
+		reports[i] = fanOutReport{Screen: r.Screen, OK: r.Err == nil}
+		result := "ok"
+		if r.Err != nil {
+			reports[i].Error, failed = strings.TrimSpace(r.Err.Error()), true
+			result = "error: " + reports[i].Error
+		} else {
+			out.Names = append(out.Names, r.Screen.Name)
+		}
+		out.Rows = append(out.Rows, []string{r.Screen.Name, pid(r.Screen), result})
+	}
+
+	if err := out.print(os.Stdout, format); err != nil {
+		return err
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// unescape replaces the escapes \n, \r, \t, \e (escape) and \\ in text typed on the command line. Other backslashes
+// are kept as they are.
+func unescape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i == len(text)-1 {
+			b.WriteByte(text[i])
+			continue
+		}
+
+		i++
+		switch text[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'e':
+			b.WriteByte(0x1b)
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestUnescape(t *testing.T) {
+	tests := map[string]string{
+		`git pull\n`: "git pull\n",
+		`a\tb\r\e[A`: "a\tb\r\x1b[A",
+		`C:\\dir\x`:  `C:\dir\x`,
+		`trailing\`:  `trailing\`,
+		"no escapes": "no escapes",
+	}
+
+	for in, want := range tests {
+		if got := unescape(in); got != want {
+			t.Errorf("unescape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats of every command, see parseFlags.
+const (
+	formatJSON  = "json"
+	formatTable = "table"
+	formatNames = "names"
+)
+
+// output is what a command prints, in every format.
+type output struct {
+	JSON   interface{} // Encoded as it is
+	Header []string
+	Rows   [][]string
+	Names  []string // Screens the command succeeded on
+}
+
+// print writes the output to w in the given format.
+func (o output) print(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.JSON)
+	case formatNames:
+		for _, name := range o.Names {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(o.Header, "\t"))
+	for _, row := range o.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = oneLine(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
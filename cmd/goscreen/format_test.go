@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOutputPrint(t *testing.T) {
+	out := output{
+		JSON:   map[string]int{"a": 1},
+		Header: []string{"NAME", "RESULT"},
+		Rows:   [][]string{{"a", "ok"}, {"bb", "error: no\nsuch screen"}},
+		Names:  []string{"a"},
+	}
+
+	tests := map[string]string{
+		formatJSON:  "{\n  \"a\": 1\n}\n",
+		formatTable: "NAME  RESULT\na     ok\nbb    error: no such screen\n",
+		formatNames: "a\n",
+	}
+	for format, want := range tests {
+		var b bytes.Buffer
+		if err := out.print(&b, format); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != want {
+			t.Errorf("%s: got %q, want %q", format, b.String(), want)
+		}
+	}
+
+	if _, _, err := parseFlags("ls", []string{"--format", "yaml"}, formatTable); err == nil {
+		t.Error("accepted an unknown format")
+	}
+}
@@ -0,0 +1,192 @@
+// Command goscreen manages GNU screen sessions from the command line, using the go-gnu-screen package.
+//
+// Usage:
+//
+//	goscreen ls                            List screens
+//	goscreen run playbook.json             Run a playbook (see screen.Playbook), "-" reads it from stdin
+//	goscreen stuff 'deploy-*' -- 'ls\n'    Type into every matching screen at once
+//	goscreen tui                           Watch screens live, and stuff, hardcopy or kill them
+//	goscreen web --addr 127.0.0.1:8080     Serve a web dashboard with live terminals (see package screenweb)
+//
+// Every command but tui and web takes --format json, table or names (one screen name per line), to be piped into other tools.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+const usage = `usage: goscreen <command> [--format json|table|names] [arguments]
+
+commands:
+  ls                             list screens
+  run <playbook.json|->          run a playbook, and print its results
+  stuff <pattern> -- <text>...   type text into every screen matching a glob pattern; \n, \r, \t, \e
+                                 and \\ in text are escapes
+  tui                            watch screens live, and stuff, hardcopy or kill them
+  web [--addr host:port]         serve a web dashboard with live terminals, on 127.0.0.1:8080 by
+      [--read-only]              default; it requires the token ($GOSCREEN_TOKEN, or a generated one
+      [--token token]            that's printed), which can type into every screen unless --read-only
+      [--tls-cert file           serve HTTPS
+       --tls-key file]
+      [--client-ca file          require client certificates signed by these CAs, with one of these
+       --allow-cn name,...]      common names, instead of a token
+      [--rate 10 --burst 20      limit requests per client, and requests and terminals at once
+       --max-in-flight 8
+       --max-terminals 16]
+
+--format names prints the screens a command succeeded on, one per line.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "ls":
+		err = ls(os.Args[2:])
+	case "run":
+		err = run(ctx, os.Args[2:])
+	case "stuff":
+		err = stuff(os.Args[2:])
+	case "tui":
+		err = runTUI(ctx, os.Args[2:])
+	case "web":
+		err = web(ctx, os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "goscreen: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goscreen:", err)
+		os.Exit(1)
+	}
+}
+
+// errFailed is returned by commands that ran, but reported a failure in their output.
+var errFailed = errors.New("failed")
+
+// parseFlags parses the flags of a command, which all have --format, and returns the format and the remaining
+// arguments.
+func parseFlags(name string, args []string, defaultFormat string) (string, []string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	format := flags.String("format", defaultFormat, "output format: json, table or names")
+	if err := flags.Parse(args); err != nil {
+		return "", nil, err
+	}
+
+	switch *format {
+	case formatJSON, formatTable, formatNames:
+		return *format, flags.Args(), nil
+	}
+	return "", nil, fmt.Errorf("unknown format %q, want json, table or names", *format)
+}
+
+// ls lists screens.
+func ls(args []string) error {
+	format, args, err := parseFlags("ls", args, formatTable)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return errors.New("usage: goscreen ls [--format json|table|names]")
+	}
+
+	screens, err := screen.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	out := output{JSON: screens, Header: []string{"NAME", "PID"}}
+	if screens == nil {
+		out.JSON = []screen.Screen{} // [] rather than null
+	}
+	for _, s := range screens {
+		out.Rows = append(out.Rows, []string{s.Name, pid(s)})
+		out.Names = append(out.Names, s.Name)
+	}
+	return out.print(os.Stdout, format)
+}
+
+// run runs a playbook.
+func run(ctx context.Context, args []string) error {
+	format, args, err := parseFlags("run", args, formatJSON)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return errors.New("usage: goscreen run [--format json|table|names] <playbook.json|->")
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	pb, err := screen.ReadPlaybook(r)
+	if err != nil {
+		return err
+	}
+
+	res := screen.NewManager().RunPlaybook(ctx, pb)
+	out := output{JSON: res, Header: []string{"SESSION", "STEP", "RESULT"}}
+	for _, session := range res.Sessions {
+		if session.OK {
+			out.Names = append(out.Names, session.Name)
+		}
+		if session.Error != "" {
+			out.Rows = append(out.Rows, []string{session.Name, "-", "error: " + session.Error})
+		}
+		for i, step := range session.Steps {
+			result := "ok"
+			if !step.OK {
+				result = "error: " + step.Error
+			}
+			out.Rows = append(out.Rows, []string{session.Name, fmt.Sprint(i), result})
+		}
+	}
+	if err = out.print(os.Stdout, format); err != nil {
+		return err
+	}
+
+	if !res.OK {
+		return errFailed
+	}
+	return nil
+}
+
+// pid formats the PID of a screen for a table.
+func pid(s screen.Screen) string {
+	if s.Process == nil {
+		return "-"
+	}
+	return fmt.Sprint(s.Process.Pid)
+}
+
+// oneLine flattens an error message for a table cell.
+func oneLine(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
@@ -0,0 +1,47 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func ioctl(f *os.File, req uintptr, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// makeRaw puts a terminal into raw mode, so keys arrive one by one and aren't echoed. restore undoes it.
+func makeRaw(f *os.File) (restore func(), err error) {
+	var old syscall.Termios
+	if err = ioctl(f, syscall.TCGETS, uintptr(unsafe.Pointer(&old))); err != nil {
+		return nil, err
+	}
+
+	// Like cfmakeraw(3), but keep output processing so "\n" still starts a new line
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR |
+		syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if err = ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&raw))); err != nil {
+		return nil, err
+	}
+
+	return func() { ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&old))) }, nil
+}
+
+// termSize returns the size of a terminal.
+func termSize(f *os.File) (cols, rows int, err error) {
+	var ws struct{ Rows, Cols, X, Y uint16 }
+	err = ioctl(f, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	return int(ws.Cols), int(ws.Rows), err
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+var errNoTerm = errors.New("goscreen tui is only supported on linux")
+
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errNoTerm
+}
+
+func termSize(f *os.File) (cols, rows int, err error) {
+	return 0, 0, errNoTerm
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+const tuiHelp = "↑/↓ select  s stuff  h hardcopy  K kill  q quit"
+
+// tui is a live view of the screens, like htop: a list of them, and what the selected one shows.
+type tui struct {
+	screens  []screen.Screen
+	selected int
+	preview  string // Hardcopy of the selected screen
+	status   string // Last message, i.e. an error
+
+	mode  byte   // 0 when browsing, 's' while typing text to stuff, 'K' while confirming a kill
+	input string // Text typed so far in 's' mode
+}
+
+// runTUI runs the dashboard until q or ctrl-c is pressed.
+func runTUI(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: goscreen tui")
+	}
+
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	fmt.Print("\x1b[?1049h\x1b[?25l")       // Switch to the alternate screen, hide the cursor
+	defer fmt.Print("\x1b[?25h\x1b[?1049l") // And back
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	t := &tui{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		t.refresh()
+		cols, rows, err := termSize(os.Stdout)
+		if err != nil {
+			return err
+		}
+		t.render(os.Stdout, cols, rows)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case key, ok := <-keys:
+			if !ok || t.handleKey(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// refresh reloads the list of screens, and the selected one's hardcopy.
+func (t *tui) refresh() {
+	screens, err := screen.ListSessions()
+	if err != nil {
+		t.status = err.Error()
+	}
+	t.setScreens(screens)
+
+	t.preview = ""
+	if s, ok := t.current(); ok {
+		if t.preview, err = s.HardcopyString(); err != nil {
+			t.status = err.Error()
+		}
+	}
+}
+
+// setScreens replaces the list of screens, keeping the same screen selected if it's still there. If it's gone, a
+// stuff or kill prompt for it is canceled.
+func (t *tui) setScreens(screens []screen.Screen) {
+	var selected string
+	if s, ok := t.current(); ok {
+		selected = s.Name
+	}
+	t.screens = screens
+
+	t.selected = 0
+	found := false
+	for i, s := range t.screens {
+		if s.Name == selected {
+			t.selected, found = i, true
+		}
+	}
+	if !found && t.mode != 0 {
+		t.mode, t.input, t.status = 0, "", selected+" is gone"
+	}
+}
+
+// current returns the selected screen.
+func (t *tui) current() (screen.Screen, bool) {
+	if t.selected < len(t.screens) {
+		return t.screens[t.selected], true
+	}
+	return screen.Screen{}, false
+}
+
+// handleKey acts on a key press. It returns true when it's time to quit.
+func (t *tui) handleKey(key []byte) bool {
+	s, ok := t.current()
+
+	switch t.mode {
+	case 's':
+		switch {
+		case string(key) == "\r" || string(key) == "\n":
+			t.mode, t.status = 0, ""
+			if ok {
+				if err := s.Stuff(t.input + "\n"); err != nil {
+					t.status = err.Error()
+				}
+			}
+		case string(key) == "\x1b":
+			t.mode, t.status = 0, ""
+		case string(key) == "\x7f" || string(key) == "\b":
+			if _, size := utf8.DecodeLastRuneInString(t.input); size > 0 {
+				t.input = t.input[:len(t.input)-size]
+			}
+		case key[0] >= ' ':
+			t.input += string(key)
+		}
+		return false
+	case 'K':
+		t.mode, t.status = 0, ""
+		if string(key) == "y" && ok {
+			if err := s.Kill(); err != nil {
+				t.status = err.Error()
+			}
+		}
+		return false
+	}
+
+	switch string(key) {
+	case "q", "\x03":
+		return true
+	case "k", "\x1b[A":
+		if t.selected > 0 {
+			t.selected--
+		}
+	case "j", "\x1b[B":
+		if t.selected < len(t.screens)-1 {
+			t.selected++
+		}
+	case "s":
+		if ok {
+			t.mode, t.input = 's', ""
+		}
+	case "K":
+		if ok {
+			t.mode = 'K'
+		}
+	case "h":
+		if ok {
+			name := s.Name + "-" + time.Now().Format("20060102-150405") + ".txt"
+			if err := os.WriteFile(name, []byte(t.preview), 0600); err != nil {
+				t.status = err.Error()
+			} else {
+				t.status = "saved " + name
+			}
+		}
+	}
+	return false
+}
+
+// render draws the dashboard onto a terminal of the given size: the list of screens, the tail of the selected one's
+// hardcopy below it, and a status line at the bottom.
+func (t *tui) render(w io.Writer, cols, rows int) {
+	var lines []string
+	lines = append(lines, fit(fmt.Sprintf("%-8s %s", "PID", "NAME"), cols))
+
+	listRows := len(t.screens)
+	if max := rows / 3; listRows > max {
+		listRows = max
+	}
+	first := 0
+	if t.selected >= listRows {
+		first = t.selected - listRows + 1
+	}
+	for i := first; i < first+listRows; i++ {
+		line := fit(fmt.Sprintf("%-8s %s", pid(t.screens[i]), t.screens[i].Name), cols)
+		if i == t.selected {
+			line = "\x1b[7m" + line + strings.Repeat(" ", cols-utf8.RuneCountInString(line)) + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+	if len(t.screens) == 0 {
+		lines = append(lines, "(no screens)")
+	}
+	lines = append(lines, strings.Repeat("─", cols))
+
+	// As much of the end of the hardcopy as fits above the status line
+	preview := strings.Split(strings.TrimRight(t.preview, " \n"), "\n")
+	if room := rows - len(lines) - 1; len(preview) > room {
+		if room < 0 {
+			room = 0
+		}
+		preview = preview[len(preview)-room:]
+	}
+	for _, line := range preview {
+		lines = append(lines, fit(line, cols))
+	}
+	for len(lines) < rows-1 {
+		lines = append(lines, "")
+	}
+
+	status := tuiHelp
+	switch {
+	case t.mode == 's':
+		status = "stuff: " + t.input + "█  (enter sends, esc cancels)"
+	case t.mode == 'K':
+		if s, ok := t.current(); ok {
+			status = "kill " + s.Name + "? (y/n)"
+		}
+	case t.status != "":
+		status = t.status
+	}
+	lines = append(lines, fit(oneLine(status), cols))
+
+	fmt.Fprint(w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+}
+
+// fit cuts a line down to cols characters.
+func fit(line string, cols int) string {
+	if utf8.RuneCountInString(line) <= cols {
+		return line
+	}
+	return string([]rune(line)[:cols])
+}
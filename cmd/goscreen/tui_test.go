@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+func TestTUI(t *testing.T) {
+	tu := &tui{
+		screens: []screen.Screen{
+			{Name: "build", Process: &os.Process{Pid: 10}},
+			{Name: "deploy", Process: &os.Process{Pid: 11}},
+		},
+		preview: "line 1\nline 2\nline 3\nline 4\n",
+	}
+
+	if tu.handleKey([]byte("\x1b[B")); tu.selected != 1 {
+		t.Errorf("selected %d after down, want 1", tu.selected)
+	}
+	if tu.handleKey([]byte("j")); tu.selected != 1 {
+		t.Errorf("selected %d after moving past the end, want 1", tu.selected)
+	}
+
+	var b bytes.Buffer
+	tu.render(&b, 20, 8)
+	lines := strings.Split(strings.TrimPrefix(b.String(), "\x1b[H\x1b[2J"), "\r\n")
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines, want 8: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[2], "\x1b[7m11       deploy") {
+		t.Errorf("selected line is %q", lines[2])
+	}
+	if lines[4] != "line 2" || lines[6] != "line 4" {
+		t.Errorf("preview doesn't end with the hardcopy's last lines: %q", lines[3:7])
+	}
+
+	tu.handleKey([]byte("s"))
+	for _, key := range []string{"l", "s", "x", "\x7f"} {
+		tu.handleKey([]byte(key))
+	}
+	if tu.mode != 's' || tu.input != "ls" {
+		t.Errorf("got mode %q, input %q while stuffing", tu.mode, tu.input)
+	}
+	tu.handleKey([]byte("\x1b"))
+	if tu.mode != 0 || !tu.handleKey([]byte("q")) {
+		t.Error("q doesn't quit after canceling")
+	}
+}
+
+func TestTUIScreenGone(t *testing.T) {
+	tu := &tui{screens: []screen.Screen{{Name: "build"}, {Name: "deploy"}}, selected: 1}
+	tu.handleKey([]byte("K"))
+	if tu.mode != 'K' {
+		t.Fatalf("got mode %q, want the kill prompt", tu.mode)
+	}
+
+	tu.setScreens(nil)
+	if tu.mode != 0 || tu.status != "deploy is gone" {
+		t.Errorf("got mode %q, status %q once the screen is gone", tu.mode, tu.status)
+	}
+	tu.render(&bytes.Buffer{}, 20, 8) // Used to index the empty list
+
+	// Prompts for screens that are still there stay up
+	tu = &tui{screens: []screen.Screen{{Name: "build"}, {Name: "deploy"}}, selected: 1}
+	tu.handleKey([]byte("s"))
+	if tu.setScreens([]screen.Screen{{Name: "deploy"}}); tu.mode != 's' || tu.selected != 0 {
+		t.Errorf("got mode %q, selected %d", tu.mode, tu.selected)
+	}
+}
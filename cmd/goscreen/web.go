@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenweb"
+)
+
+const webUsage = "usage: goscreen web [--addr host:port] [--read-only] [--token token] " +
+	"[--tls-cert file --tls-key file [--client-ca file --allow-cn name,...]] " +
+	"[--rate n] [--burst n] [--max-in-flight n] [--max-terminals n]"
+
+// web serves the web dashboard until ctx is done.
+func web(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("web", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	readOnly := flags.Bool("read-only", false, "let browsers watch screens, but not type into them")
+	token := flags.String("token", "", "API token to require, $GOSCREEN_TOKEN or generated if empty")
+	certFile := flags.String("tls-cert", "", "serve HTTPS with this certificate")
+	keyFile := flags.String("tls-key", "", "key of the certificate")
+	clientCA := flags.String("client-ca", "", "require client certificates signed by these CAs, instead of a token")
+	allowCN := flags.String("allow-cn", "", "comma separated common names of the client certificates to accept")
+	limiter := &screenweb.Limiter{}
+	flags.Float64Var(&limiter.RequestsPerSecond, "rate", 10, "requests per second per client, 0 for unlimited")
+	flags.IntVar(&limiter.Burst, "burst", 20, "requests a client can make at once")
+	flags.IntVar(&limiter.MaxInFlight, "max-in-flight", 8, "requests handled at once, 0 for unlimited")
+	flags.IntVar(&limiter.MaxTerminals, "max-terminals", 16, "terminals open at once, 0 for unlimited")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		*token = os.Getenv("GOSCREEN_TOKEN") // Not the flag's default, which -h would print
+	}
+	if flags.NArg() != 0 || (*certFile == "") != (*keyFile == "") || (*clientCA != "" && (*certFile == "" || *allowCN == "")) {
+		return errors.New(webUsage)
+	}
+
+	h := screenweb.NewHandler(nil)
+	h.ReadOnly = *readOnly
+	limited := limiter.Middleware(h)
+	srv := &http.Server{Addr: *addr}
+	scheme, query := "http", ""
+
+	if *certFile != "" {
+		config, err := screenweb.TLSConfig(*certFile, *keyFile, *clientCA)
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig, scheme = config, "https"
+	}
+
+	if *clientCA != "" {
+		names := screenweb.CommonNames{}
+		for _, cn := range strings.Split(*allowCN, ",") {
+			names[strings.TrimSpace(cn)] = screenweb.ScopeAll
+		}
+		srv.Handler = names.Middleware(limited)
+	} else {
+		if *token == "" {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				return err
+			}
+			*token = hex.EncodeToString(b)
+			query = "?token=" + *token // Only printed when nobody else knows it yet, so it doesn't end up in logs
+		}
+		srv.Handler = screenweb.Tokens{*token: screenweb.ScopeAll}.Middleware(limited)
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "goscreen: serving on %s://%s/%s\n", scheme, *addr, query)
+	var err error
+	if srv.TLSConfig != nil {
+		err = srv.ListenAndServeTLS("", "") // The certificate is in TLSConfig
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+var (
+	// ErrSessionExists is returned by New when a screen with the name exists already, or showed up while creating it.
+	ErrSessionExists = errors.New("screen already exists")
+	// ErrStartupFailed is returned by New when the screen it started died right away, i.e. because its shell doesn't
+	// exist. screen itself reports success in that case.
+	ErrStartupFailed = errors.New("screen died while starting")
+)
+
+// DuplicatePolicy is what New does when another screen with the same name shows up while it creates one, i.e. from
+// another process calling New at the same time. Either way, only the oldest screen of the name survives.
+type DuplicatePolicy int
+
+const (
+	DuplicateRollback DuplicatePolicy = iota // Quit the new screen, and return ErrSessionExists
+	DuplicateAdopt                           // Quit the new screen, and return the existing one instead
+)
+
+// CreateHook sets up a screen New just made, see Manager.OnCreate.
+type CreateHook func(s Screen) error
+
+// CreateCommands returns a CreateHook running screen commands, i.e. "defscrollback 10000" or "hardstatus on", like
+// lines of a screenrc. See Screen.SourceLines.
+func CreateCommands(lines ...string) CreateHook {
+	return func(s Screen) error {
+		return s.SourceLines(lines)
+	}
+}
+
+// setUp sets the Manager's WindowTitle as the shelltitle of a screen New just made, then runs its OnCreate hooks. If
+// one fails, the screen is quit, so there are no half set up screens around.
+func (m *Manager) setUp(s Screen) error {
+	if m.WindowTitle != "" {
+		if err := s.builtinTemplateArgs("shelltitle", m.WindowTitle); err != nil {
+			s.Quit()
+			return fmt.Errorf("setting up %q: %w", s.Name, err)
+		}
+	}
+	for _, hook := range m.OnCreate {
+		if err := hook(s); err != nil {
+			s.Quit()
+			return fmt.Errorf("setting up %q: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// createTokenVar is set in the environment of screens New makes, so it can tell its own screen from others of the
+// same name.
+const createTokenVar = "GO_GNU_SCREEN_CREATE"
+
+// errSessionExists returns ErrSessionExists in the repo's error style.
+func errSessionExists() error {
+	return &os.SyscallError{Syscall: os.ErrExist.Error(), Err: ErrSessionExists}
+}
+
+// newCreateToken returns a random token for createTokenVar.
+func newCreateToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// waitCreated waits until the screen New started with token shows up, then settles duplicates of its name, see
+// settleDuplicates. If it doesn't show up in time, or only as dead, it returns ErrStartupFailed with out, what
+// starting it printed. Whenever it gives up, our screen is quit if it's there after all.
+func (m *Manager) waitCreated(ctx context.Context, name, token string, out []byte) (s Screen, adopted bool, err error) {
+	deadline := time.Now().Add(m.startupGrace())
+	for {
+		if ctx.Err() != nil {
+			m.abandon(name, token)
+			return Screen{}, false, ctx.Err()
+		}
+		time.Sleep(m.startupPoll())
+
+		screens, dead := m.named(name)
+		if len(screens) == 0 && dead {
+			return Screen{}, false, startupFailed(name, out)
+		}
+		for i, own := range screens {
+			switch m.hasToken(own, token) {
+			case tokenFound:
+				// Give screens started at the same time a moment to show up too
+				time.Sleep(m.startupPoll())
+				screens, _ = m.named(name)
+				return m.settleDuplicates(own, screens)
+			case tokenUnknown:
+				if len(screens) == 1 {
+					return screens[i], false, nil // Can't tell whose it is, but there's no duplicate either
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			m.abandon(name, token) // Ours may be there, but couldn't be told apart from someone else's
+			return Screen{}, false, startupFailed(name, out)
+		}
+	}
+}
+
+// abandon quits the screen New started with token, once it gave up waiting for it, so it isn't left running. If
+// tokens can't be read (i.e. without /proc), the newest screen of the name whose token is unknown is taken for ours.
+func (m *Manager) abandon(name, token string) {
+	screens, _ := m.named(name)
+	var newest *Screen
+	for i, s := range screens {
+		switch m.hasToken(s, token) {
+		case tokenFound:
+			s.Quit()
+			return
+		case tokenUnknown:
+			newest = &screens[i] // Sorted oldest first
+		}
+	}
+	if newest != nil {
+		newest.Quit()
+	}
+}
+
+// tokenState is whether a screen was started with a create token.
+type tokenState int
+
+const (
+	tokenMissing tokenState = iota
+	tokenFound
+	tokenUnknown // Its environment can't be read
+)
+
+// hasToken checks the environment of a screen's process for createTokenVar set to token.
+func (m *Manager) hasToken(s Screen, token string) tokenState {
+	environ, err := m.readFile("/proc/" + strconv.Itoa(s.Process.Pid) + "/environ")
+	if err != nil {
+		return tokenUnknown
+	}
+	for _, kv := range bytes.Split(environ, []byte{0}) {
+		if string(kv) == createTokenVar+"="+token {
+			return tokenFound
+		}
+	}
+	return tokenMissing
+}
+
+// startupFailed returns ErrStartupFailed for the named screen, with what starting it printed.
+func startupFailed(name string, out []byte) error {
+	err := fmt.Errorf("%w: %q exited right away", ErrStartupFailed, name)
+	if text := strings.TrimSpace(string(out)); text != "" {
+		err = fmt.Errorf("%w: %q exited right away: %s", ErrStartupFailed, name, text)
+	}
+	return err
+}
+
+// named lists the live screens with exactly the given name, oldest first, and whether there are dead ones of the name.
+// Screens whose start time is unknown sort by PID, after the others.
+func (m *Manager) named(name string) (res []Screen, dead bool) {
+	out, _ := m.combined("screen", "-ls", name)
+
+	for _, e := range screenparse.ParseList(string(out)) {
+		if e.Name != name {
+			continue
+		}
+		if e.Status.Dead {
+			dead = true
+			continue
+		}
+		s := Screen{Name: name, Mutex: m.mutex(name), manager: m}
+		s.Process, _ = os.FindProcess(e.PID)
+		s.startTime, _ = m.procStartTime(e.PID)
+		res = append(res, s)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if (a.startTime == 0) != (b.startTime == 0) {
+			return a.startTime != 0
+		}
+		if a.startTime != b.startTime {
+			return a.startTime < b.startTime
+		}
+		return a.Process.Pid < b.Process.Pid
+	})
+	return res, dead
+}
+
+// settleDuplicates keeps own if it's the oldest of the screens with its name. Otherwise it quits own, and adopts the
+// oldest or returns ErrSessionExists, as the Manager's OnDuplicate says. Every creator comes to the same conclusion.
+func (m *Manager) settleDuplicates(own Screen, screens []Screen) (Screen, bool, error) {
+	if len(screens) == 0 || screens[0].Process.Pid == own.Process.Pid {
+		return own, false, nil
+	}
+
+	if _, err := m.combined(screenExec, "-S", own.target(), "-X", "quit"); err != nil {
+		return Screen{}, false, err
+	}
+	if m.OnDuplicate == DuplicateAdopt {
+		return screens[0], true, nil
+	}
+	return Screen{}, false, errSessionExists()
+}
@@ -0,0 +1,241 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// racingRunner plays another process creating a screen of the same name, just before the screen New starts.
+type racingRunner struct {
+	*fakeRunner
+	list   string // Output of screen -ls once created
+	noProc bool   // Environments of processes can't be read, like on hosts without /proc
+}
+
+func (r *racingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "env" {
+		r.outputs[strings.Join(append([]string{name}, args...), " ")] = ""
+		r.outputs["screen -ls race"] = r.list
+		delete(r.exits, "screen -ls race")
+		if !r.noProc {
+			r.outputs["cat /proc/4301/environ"] = "HOME=/root\x00" + args[0] + "\x00"
+		}
+	}
+	return r.fakeRunner.Run(ctx, name, args...)
+}
+
+// fakeStat returns a /proc/<pid>/stat with the given start time.
+func fakeStat(pid, start string) string {
+	return pid + " (screen) S" + strings.Repeat(" 0", 18) + " " + start + " 0 0\n"
+}
+
+func TestNewDuplicate(t *testing.T) {
+	for _, policy := range []DuplicatePolicy{DuplicateRollback, DuplicateAdopt} {
+		r := &racingRunner{
+			fakeRunner: &fakeRunner{outputs: map[string]string{
+				"cat /proc/4300/environ":               "HOME=/root\x00",
+				"cat /proc/4300/stat":                  fakeStat("4300", "500"),
+				"cat /proc/4301/stat":                  fakeStat("4301", "501"),
+				"/usr/bin/screen -S 4301.race -X quit": "",
+			}},
+			list: "There are screens on:\n\t4301.race\t(Detached)\n\t4300.race\t(Detached)\n2 Sockets in /run/screen/S-root.\n",
+		}
+		r.exits = map[string]int{"screen -ls race": 1}
+		m := NewManagerWithRunner(r)
+		m.DefaultShell = "/bin/sh"
+		m.Defaults.StartupPoll = time.Millisecond
+		m.OnDuplicate = policy
+
+		s, err := m.New(context.Background(), "race")
+		switch policy {
+		case DuplicateRollback:
+			if !errors.Is(err, ErrSessionExists) {
+				t.Errorf("got %+v, %v, want ErrSessionExists", s, err)
+			}
+		case DuplicateAdopt:
+			if err != nil || s.Process.Pid != 4300 {
+				t.Errorf("got %+v, %v, want the older screen", s, err)
+			}
+		}
+		if !strings.Contains(strings.Join(r.ran, "\n"), "-S 4301.race -X quit") {
+			t.Error("the newer screen wasn't quit")
+		}
+	}
+}
+
+func TestNewExists(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls a+b": fakeList}})
+	if _, err := m.New(context.Background(), "a+b"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("got %v, want ErrSessionExists", err)
+	}
+}
+
+func TestNewStartupFailed(t *testing.T) {
+	for _, list := range []string{
+		"No Sockets found in /run/screen/S-root.\n",
+		"There is a screen on:\n\t4302.race\t(Dead ???)\nRemove dead screens with 'screen -wipe'.\n1 Socket in /run/screen/S-root.\n",
+	} {
+		r := &racingRunner{fakeRunner: &fakeRunner{outputs: map[string]string{}}, list: list}
+		r.exits = map[string]int{"screen -ls race": 1}
+		m := NewManagerWithRunner(r)
+		m.DefaultShell = "/nonexistent"
+		m.Defaults.StartupPoll = time.Millisecond
+		m.Defaults.StartupGrace = time.Millisecond * 20
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if _, err := m.New(ctx, "race"); !errors.Is(err, ErrStartupFailed) {
+			t.Errorf("got %v, want ErrStartupFailed", err)
+		}
+		cancel()
+	}
+}
+
+// argvRunner records the arguments of the command starting a screen, and fails it.
+type argvRunner struct {
+	*fakeRunner
+	argv []string
+}
+
+func (r *argvRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "env" {
+		r.argv = args
+	}
+	return r.fakeRunner.Run(ctx, name, args...)
+}
+
+func TestNewArgv(t *testing.T) {
+	r := &argvRunner{fakeRunner: &fakeRunner{outputs: map[string]string{}, exits: map[string]int{"screen -ls race": 1}}}
+	m := NewManagerWithRunner(r)
+	m.DefaultShell = "/opt/my tools/bash"
+	m.DefaultShellArgs = []string{"--login", "-o", "vi"}
+
+	for _, shell := range [][]string{nil, {"/opt/my tools/bash", "--login", "-o", "vi"}} {
+		m.New(context.Background(), "race", shell...)
+		if got := strings.Join(r.argv[len(r.argv)-4:], "|"); got != "/opt/my tools/bash|--login|-o|vi" {
+			t.Errorf("%q: got argv ending in %q", shell, got)
+		}
+	}
+}
+
+func TestNewOnCreate(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		r := &racingRunner{
+			fakeRunner: &fakeRunner{outputs: map[string]string{
+				"cat /proc/4301/stat":                  fakeStat("4301", "501"),
+				"/usr/bin/screen -S 4301.race -X quit": "",
+			}},
+			list: "There is a screen on:\n\t4301.race\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+		}
+		r.exits = map[string]int{"screen -ls race": 1}
+		m := NewManagerWithRunner(r)
+		m.DefaultShell = "/bin/sh"
+		m.Defaults.StartupPoll = time.Millisecond
+
+		var hooked []string
+		m.OnCreate = []CreateHook{func(s Screen) error {
+			hooked = append(hooked, s.Name)
+			return nil
+		}}
+		if fail {
+			m.OnCreate = append(m.OnCreate, CreateCommands("no-such-command"))
+		}
+		var created int
+		m.OnEvent = func(e Event) { created++ }
+
+		s, err := m.New(context.Background(), "race")
+		if len(hooked) != 1 || hooked[0] != "race" {
+			t.Errorf("hooks ran on %q", hooked)
+		}
+		quit := strings.Contains(strings.Join(r.ran, "\n"), "-X quit")
+		if fail && (err == nil || !quit || created != 0) {
+			t.Errorf("got %+v, %v, quit %v, %d events after a failing hook", s, err, quit, created)
+		} else if !fail && (err != nil || quit || created != 1) {
+			t.Errorf("got %+v, %v, quit %v, %d events", s, err, quit, created)
+		}
+	}
+}
+
+func TestNewWindowTitle(t *testing.T) {
+	r := &racingRunner{
+		fakeRunner: &fakeRunner{outputs: map[string]string{
+			"cat /proc/4301/stat": fakeStat("4301", "501"),
+			"/usr/bin/screen -S 4301.race -X shelltitle build log": "",
+		}},
+		list: "There is a screen on:\n\t4301.race\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+	}
+	r.exits = map[string]int{"screen -ls race": 1}
+	m := NewManagerWithRunner(r)
+	m.DefaultShell = "/bin/sh"
+	m.Defaults.StartupPoll = time.Millisecond
+	m.WindowTitle = "build log"
+
+	if _, err := m.New(context.Background(), "race"); err != nil {
+		t.Fatal(err)
+	}
+	if ran := strings.Join(r.ran, "\n"); !strings.Contains(ran, "-dmS race -t build log /bin/sh") {
+		t.Errorf("screen wasn't started with the title:\n%s", ran)
+	}
+}
+
+func TestNewWithoutProc(t *testing.T) {
+	// Two screens of the name show up and neither can be told apart, so New gives up, taking the newer one with it
+	for _, timeout := range []time.Duration{time.Second, time.Millisecond * 20} {
+		r := &racingRunner{
+			fakeRunner: &fakeRunner{outputs: map[string]string{
+				"/usr/bin/screen -S 4301.race -X quit": "",
+			}},
+			list:   "There are screens on:\n\t4301.race\t(Detached)\n\t4300.race\t(Detached)\n2 Sockets in /run/screen/S-root.\n",
+			noProc: true,
+		}
+		r.exits = map[string]int{"screen -ls race": 1}
+		m := NewManagerWithRunner(r)
+		m.DefaultShell = "/bin/sh"
+		m.Defaults.StartupPoll = time.Millisecond
+		m.Defaults.StartupGrace = time.Millisecond * 100
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err := m.New(ctx, "race")
+		cancel()
+		if timeout == time.Second && !errors.Is(err, ErrStartupFailed) {
+			t.Errorf("got %v, want ErrStartupFailed", err)
+		} else if timeout < time.Second && !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want the context's error", err)
+		}
+		ran := strings.Join(r.ran, "\n")
+		if !strings.Contains(ran, "-S 4301.race -X quit") || strings.Contains(ran, "-S 4300.race -X quit") {
+			t.Errorf("%v: didn't quit just the newer screen:\n%s", timeout, ran)
+		}
+	}
+}
+
+// hangingRunner hangs creating screens, until the context is done.
+type hangingRunner struct {
+	*fakeRunner
+}
+
+func (r *hangingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "env" {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+	return r.fakeRunner.Run(ctx, name, args...)
+}
+
+func TestNewCanceled(t *testing.T) {
+	r := &hangingRunner{&fakeRunner{exits: map[string]int{"screen -ls hang": 1}}}
+	m := NewManagerWithRunner(r)
+	m.DefaultShell = "/bin/sh"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	start := time.Now()
+	if _, err := m.New(ctx, "hang"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the context's error", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("took %v", d)
+	}
+}
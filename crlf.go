@@ -0,0 +1,47 @@
+package screen
+
+import (
+	"strings"
+)
+
+// LineEnding is what a line break in stuffed text is typed as.
+type LineEnding int
+
+const (
+	LineEndingLF   LineEnding = iota // "\n", as it is
+	LineEndingCR                     // "\r", what a terminal sends for Enter; serial and telnet windows often want this
+	LineEndingCRLF                   // "\r\n"
+)
+
+// StuffOptions changes how StuffWith types text.
+type StuffOptions struct {
+	LineEnding LineEnding // Line breaks ("\n" or "\r\n") in the text are typed as this
+}
+
+// StuffWith pastes text into the screen's stdin like Stuff, changed as opts says.
+func (s Screen) StuffWith(opts StuffOptions, commands ...string) error {
+	res := make([]string, len(commands))
+	for i, c := range commands {
+		res[i] = opts.LineEnding.apply(c)
+	}
+	return s.Stuff(res...)
+}
+
+// apply replaces the line breaks in text with the ending.
+func (e LineEnding) apply(text string) string {
+	switch e {
+	case LineEndingCR:
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r")
+	case LineEndingCRLF:
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	}
+	return text
+}
+
+// SetCRLF sets whether text copied in copy mode ends its lines with "\r\n" rather than "\n", like "crlf".
+func (s Screen) SetCRLF(on bool) error {
+	if on {
+		return s.builtinTemplateArgs("crlf", "on")
+	}
+	return s.builtinTemplateArgs("crlf", "off")
+}
@@ -0,0 +1,37 @@
+package screen
+
+import (
+	"testing"
+)
+
+func TestLineEnding(t *testing.T) {
+	const text = "user\nsecret\r\nshow run\n"
+	for e, want := range map[LineEnding]string{
+		LineEndingLF:   text,
+		LineEndingCR:   "user\rsecret\rshow run\r",
+		LineEndingCRLF: "user\r\nsecret\r\nshow run\r\n",
+	} {
+		if got := e.apply(text); got != want {
+			t.Errorf("%d: got %q, want %q", e, got, want)
+		}
+	}
+}
+
+func TestStuffWith(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b": fakeList,
+		"/usr/bin/screen -S 4242.a+b -X stuff AT\r": "",
+		"/usr/bin/screen -S 4242.a+b -X crlf on":    "",
+	}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.StuffWith(StuffOptions{LineEnding: LineEndingCR}, "AT\n"); err != nil {
+		t.Error(err)
+	}
+	if err = s.SetCRLF(true); err != nil {
+		t.Error(err)
+	}
+}
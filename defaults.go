@@ -0,0 +1,56 @@
+package screen
+
+import "time"
+
+// Defaults are the timings a Manager waits and polls with, for tuning it to slow hosts or fast CI. Zero fields use the
+// default noted on them.
+type Defaults struct {
+	// StartupPoll is how often New checks whether a screen it started is up, 100 milliseconds by default.
+	StartupPoll time.Duration
+	// StartupGrace is how long New waits for a screen it started to show up, before deciding it died right away
+	// (i.e. because its shell doesn't exist) and returning ErrStartupFailed. 5 seconds by default.
+	StartupGrace time.Duration
+	// CommandTimeout limits each command run on the host (not streamed ones, like Capture), no limit by default.
+	CommandTimeout time.Duration
+	// OutputPoll is how often output and exit statuses are read back while waiting for them (StuffReturnGetOutput,
+	// ExecProcess.Wait, CheckResponsive), 500 milliseconds by default.
+	OutputPoll time.Duration
+	// QueryTTL is how long replies of Screen.Query (and what's built on it, like Windows) are reused, unless a
+	// command is sent to the screen in the meantime. 250 milliseconds by default, negative disables caching.
+	QueryTTL time.Duration
+	// SettleDelay is how long StuffReturnGetOutput gives screen to start writing its logfile before stuffing the
+	// command, 2 seconds by default.
+	SettleDelay time.Duration
+}
+
+// startupPoll returns the Manager's Defaults.StartupPoll, or its default.
+func (m *Manager) startupPoll() time.Duration {
+	if m.Defaults.StartupPoll <= 0 {
+		return time.Millisecond * 100
+	}
+	return m.Defaults.StartupPoll
+}
+
+// startupGrace returns the Manager's Defaults.StartupGrace, or its default.
+func (m *Manager) startupGrace() time.Duration {
+	if m.Defaults.StartupGrace <= 0 {
+		return time.Second * 5
+	}
+	return m.Defaults.StartupGrace
+}
+
+// outputPoll returns the Manager's Defaults.OutputPoll, or its default.
+func (m *Manager) outputPoll() time.Duration {
+	if m.Defaults.OutputPoll <= 0 {
+		return time.Millisecond * 500
+	}
+	return m.Defaults.OutputPoll
+}
+
+// settleDelay returns the Manager's Defaults.SettleDelay, or its default.
+func (m *Manager) settleDelay() time.Duration {
+	if m.Defaults.SettleDelay <= 0 {
+		return time.Second * 2
+	}
+	return m.Defaults.SettleDelay
+}
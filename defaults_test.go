@@ -0,0 +1,40 @@
+package screen
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// blockingRunner runs every command until its context is done.
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestDefaultsCommandTimeout(t *testing.T) {
+	m := NewManagerWithRunner(blockingRunner{})
+	m.Defaults.CommandTimeout = time.Millisecond * 10
+
+	start := time.Now()
+	if _, err := m.Get("build"); err == nil {
+		t.Error("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s", elapsed)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	m := NewManager()
+	if m.startupPoll() != time.Millisecond*100 || m.outputPoll() != time.Millisecond*500 || m.settleDelay() != time.Second*2 {
+		t.Error("unexpected defaults")
+	}
+
+	m.Defaults = Defaults{StartupPoll: time.Second, OutputPoll: time.Second, SettleDelay: time.Millisecond}
+	if m.startupPoll() != time.Second || m.outputPoll() != time.Second || m.settleDelay() != time.Millisecond {
+		t.Error("Defaults ignored")
+	}
+}
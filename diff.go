@@ -0,0 +1,83 @@
+package screen
+
+import "strings"
+
+// ChangeKind is whether a line was added or removed between two snapshots.
+type ChangeKind int
+
+const (
+	LineAdded ChangeKind = iota + 1
+	LineRemoved
+)
+
+// String returns "+" or "-", like a unified diff.
+func (k ChangeKind) String() string {
+	switch k {
+	case LineAdded:
+		return "+"
+	case LineRemoved:
+		return "-"
+	}
+	return "?"
+}
+
+// Change is a line that differs between two snapshots, see DiffSnapshots.
+type Change struct {
+	Kind ChangeKind
+	Line int // 1-based, in a for removed lines and in b for added ones
+	Text string
+}
+
+// DiffSnapshots returns the lines that changed from a to b, which are hardcopies or other captures of a screen (see
+// HardcopyString and SnapshotEvery). Lines that stayed the same aren't returned. Removals come before the additions
+// that replace them, like in a unified diff.
+func DiffSnapshots(a, b string) []Change {
+	as, bs := snapshotLines(a), snapshotLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of as[i:] and bs[j:]
+	lcs := make([][]int, len(as)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var res []Change
+	i, j := 0, 0
+	for i < len(as) || j < len(bs) {
+		switch {
+		case i < len(as) && j < len(bs) && as[i] == bs[j]:
+			i, j = i+1, j+1
+		case j == len(bs) || (i < len(as) && lcs[i+1][j] >= lcs[i][j+1]):
+			res = append(res, Change{Kind: LineRemoved, Line: i + 1, Text: as[i]})
+			i++
+		default:
+			res = append(res, Change{Kind: LineAdded, Line: j + 1, Text: bs[j]})
+			j++
+		}
+	}
+	return res
+}
+
+// snapshotLines splits a snapshot into lines, ignoring trailing whitespace, which screen pads lines with.
+func snapshotLines(text string) []string {
+	text = strings.TrimRight(text, " \t\r\n")
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return lines
+}
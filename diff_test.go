@@ -0,0 +1,28 @@
+package screen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	a := "$ make\nbuilding...\n[ 10%] foo.o\n\n\n"
+	b := "$ make\nbuilding...\n[ 50%] bar.o   \n[100%] done\n$\n"
+
+	want := []Change{
+		{LineRemoved, 3, "[ 10%] foo.o"},
+		{LineAdded, 3, "[ 50%] bar.o"},
+		{LineAdded, 4, "[100%] done"},
+		{LineAdded, 5, "$"},
+	}
+	if got := DiffSnapshots(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := DiffSnapshots(b, b); len(got) != 0 {
+		t.Errorf("got %v for identical snapshots", got)
+	}
+	if got := DiffSnapshots("", "x"); !reflect.DeepEqual(got, []Change{{LineAdded, 1, "x"}}) {
+		t.Errorf("got %v from an empty snapshot", got)
+	}
+}
@@ -0,0 +1,14 @@
+package screen
+
+const dockerExec = "docker"
+
+// NewDockerManager returns a Manager that runs every screen command inside the given container through "docker exec".
+// The container must be running and have screen installed. Files such as hardcopies and logs live inside the container,
+// and are read back out through the same exec channel.
+//
+// Extra arguments are passed to "docker exec" before the container name, i.e. "-u", "builder" to run as another user.
+func NewDockerManager(container string, execArgs ...string) *Manager {
+	prefix := append([]string{dockerExec, "exec"}, execArgs...)
+	ttyPrefix := append([]string{dockerExec, "exec", "-it"}, execArgs...)
+	return NewManagerWithRunner(ExecRunner{Prefix: append(prefix, container), TTYPrefix: append(ttyPrefix, container)})
+}
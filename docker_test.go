@@ -0,0 +1,17 @@
+package screen
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDockerManagerCommand(t *testing.T) {
+	m := NewDockerManager("web", "-u", "builder")
+	cmd, _ := m.command(context.Background(), screenExec, "-ls")
+
+	want := []string{"docker", "exec", "-u", "builder", "web", screenExec, "-ls"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("got %q, want %q", cmd.Args, want)
+	}
+}
@@ -0,0 +1,183 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ErrExpectTimeout is returned by a Driver when the output it waits for doesn't show up in time.
+var ErrExpectTimeout = errors.New("timed out waiting for output")
+
+// Driver automates an interactive program running in a screen, like a network device's CLI or an installer, by
+// typing commands and waiting for their output:
+//
+//	d, _ := s.Driver(ctx)
+//	defer d.Close()
+//	err := d.Cmd("configure terminal").Expect(`\(config\)#`).Cmd("hostname edge-1").WaitPrompt().Err()
+//
+// Every step returns the Driver so steps can be chained. Once a step fails, the remaining ones do nothing, and Err
+// returns the failure. A Driver captures the screen's output, so don't use Log while it's open.
+type Driver struct {
+	// Timeout is how long Expect waits, 30 seconds if zero.
+	Timeout time.Duration
+	// Prompt matches the program's prompt, see WaitPrompt. It defaults to a line ending in "$ ", "# ", "> " or "% ".
+	Prompt *regexp.Regexp
+
+	ctx    context.Context
+	send   func(text string) error
+	closer io.Closer
+
+	mutex   sync.Mutex
+	buf     []byte        // Output that wasn't consumed by Expect yet
+	readErr error         // Why reading output stopped
+	notify  chan struct{} // Signaled when buf or readErr changes
+
+	output string // What the last Expect consumed
+	err    error
+}
+
+var defaultPrompt = regexp.MustCompile(`[$#>%] ?$`)
+
+// Driver starts driving the screen. Canceling ctx fails the step that's waiting, and every step after it.
+func (s Screen) Driver(ctx context.Context) (*Driver, error) {
+	c, err := s.Capture()
+	if err != nil {
+		return nil, err
+	}
+	return newDriver(ctx, c, s.Stuff, c), nil
+}
+
+// newDriver returns a Driver reading output from r and typing with send.
+func newDriver(ctx context.Context, r io.Reader, send func(...string) error, closer io.Closer) *Driver {
+	d := &Driver{
+		ctx:    ctx,
+		send:   func(text string) error { return send(text) },
+		closer: closer,
+		notify: make(chan struct{}, 1),
+	}
+	go d.read(r)
+	return d
+}
+
+// read collects output until r fails.
+func (d *Driver) read(r io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+
+		d.mutex.Lock()
+		d.buf = append(d.buf, buf[:n]...)
+		if err != nil {
+			d.readErr = err
+		}
+		d.mutex.Unlock()
+
+		select {
+		case d.notify <- struct{}{}:
+		default:
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Send types text into the screen as it is.
+func (d *Driver) Send(text string) *Driver {
+	if d.err == nil {
+		d.err = d.send(text)
+	}
+	return d
+}
+
+// Cmd types a line into the screen, followed by a newline.
+func (d *Driver) Cmd(line string) *Driver {
+	return d.Send(line + "\n")
+}
+
+// Expect waits for output matching the regular expression pattern. Output up to and including the match is consumed,
+// and available from Output, so the next Expect only looks at what came after it.
+func (d *Driver) Expect(pattern string) *Driver {
+	if d.err != nil {
+		return d
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		d.err = err
+		return d
+	}
+	return d.expect(re)
+}
+
+// WaitPrompt waits for the program's prompt, see Driver.Prompt.
+func (d *Driver) WaitPrompt() *Driver {
+	if d.err != nil {
+		return d
+	}
+
+	prompt := d.Prompt
+	if prompt == nil {
+		prompt = defaultPrompt
+	}
+	return d.expect(prompt)
+}
+
+// expect waits for output matching re.
+func (d *Driver) expect(re *regexp.Regexp) *Driver {
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = time.Second * 30
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		d.mutex.Lock()
+		loc := re.FindIndex(d.buf)
+		if loc != nil {
+			d.output = string(d.buf[:loc[1]])
+			d.buf = d.buf[loc[1]:]
+		}
+		readErr := d.readErr
+		d.mutex.Unlock()
+
+		switch {
+		case loc != nil:
+			return d
+		case readErr != nil:
+			d.err = fmt.Errorf("expecting %q: %w", re, readErr)
+			return d
+		}
+
+		select {
+		case <-d.notify:
+		case <-timer.C:
+			d.err = fmt.Errorf("expecting %q: %w", re, ErrExpectTimeout)
+			return d
+		case <-d.ctx.Done():
+			d.err = fmt.Errorf("expecting %q: %w", re, d.ctx.Err())
+			return d
+		}
+	}
+}
+
+// Output returns the output consumed by the last successful Expect or WaitPrompt, including what matched.
+func (d *Driver) Output() string {
+	return d.output
+}
+
+// Err returns why a step failed, or nil if all of them succeeded.
+func (d *Driver) Err() error {
+	return d.err
+}
+
+// Close stops capturing the screen's output.
+func (d *Driver) Close() error {
+	return d.closer.Close()
+}
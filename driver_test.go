@@ -0,0 +1,51 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDevice answers every line typed into it through a pipe, like a network device's CLI.
+func fakeDevice(t *testing.T, answer func(line string) string) *Driver {
+	r, w := io.Pipe()
+	t.Cleanup(func() { w.Close() })
+
+	send := func(text ...string) error {
+		go w.Write([]byte(answer(strings.Join(text, " "))))
+		return nil
+	}
+	d := newDriver(context.Background(), r, send, r)
+	return d
+}
+
+func TestDriver(t *testing.T) {
+	d := fakeDevice(t, func(line string) string {
+		switch line {
+		case "configure terminal\n":
+			return line + "router(config)# "
+		case "show hostname\n":
+			return line + "edge-1\nrouter(config)# "
+		}
+		return line + "% Invalid input\nrouter# "
+	})
+	d.Timeout = time.Millisecond * 200
+
+	err := d.Cmd("configure terminal").Expect(`\(config\)#`).Cmd("show hostname").WaitPrompt().Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(d.Output(), "edge-1\n") {
+		t.Errorf("got output %q", d.Output())
+	}
+
+	if err = d.Cmd("reload").Expect(`\(config\)#`).Err(); !errors.Is(err, ErrExpectTimeout) {
+		t.Errorf("got %v, want ErrExpectTimeout", err)
+	}
+	if err = d.Cmd("exit").WaitPrompt().Err(); !errors.Is(err, ErrExpectTimeout) {
+		t.Errorf("got %v, a failed driver should stay failed", err)
+	}
+}
@@ -0,0 +1,32 @@
+package screen
+
+import (
+	"path"
+	"strings"
+)
+
+// withEnv returns the command line running a command in the Manager's Env, with extra variables on top. Only screen
+// itself gets the Manager's Env, the tools the package runs around it keep the host's.
+func (m *Manager) withEnv(name string, args []string, extra ...string) (string, []string) {
+	isolated := m.Env != nil && path.Base(name) == "screen"
+	if !isolated && len(extra) == 0 {
+		return name, args
+	}
+
+	var params []string
+	if isolated {
+		params = append(append(params, "-i"), m.Env...)
+	}
+	params = append(append(params, extra...), name)
+	return "env", append(params, args...)
+}
+
+// lookupEnv returns a variable of the Manager's Env, and whether the Manager has an Env at all.
+func (m *Manager) lookupEnv(key string) (value string, isolated bool) {
+	for _, kv := range m.Env {
+		if strings.HasPrefix(kv, key+"=") {
+			return kv[len(key)+1:], true
+		}
+	}
+	return "", m.Env != nil
+}
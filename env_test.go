@@ -0,0 +1,33 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithEnv(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{})
+	line := func(name string, args []string, extra ...string) string {
+		name, args = m.withEnv(name, args, extra...)
+		return strings.Join(append([]string{name}, args...), " ")
+	}
+
+	if got := line(screenExec, []string{"-dmS", "x"}, "TOKEN=1"); got != "env TOKEN=1 /usr/bin/screen -dmS x" {
+		t.Errorf("without Env, got %q", got)
+	}
+
+	m.Env = []string{"PATH=/bin", "LC_ALL=C"}
+	for _, c := range []struct {
+		name  string
+		extra []string
+		want  string
+	}{
+		{screenExec, []string{"TOKEN=1"}, "env -i PATH=/bin LC_ALL=C TOKEN=1 /usr/bin/screen -dmS x"},
+		{"screen", nil, "env -i PATH=/bin LC_ALL=C screen -dmS x"},
+		{"ps", nil, "ps -dmS x"},
+	} {
+		if got := line(c.name, []string{"-dmS", "x"}, c.extra...); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+}
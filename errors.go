@@ -0,0 +1,94 @@
+package screen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+var (
+	// ErrNotInstalled is returned when the screen binary can't be found on the Manager's host.
+	ErrNotInstalled = errors.New("screen is not installed")
+	// ErrSocketDirPermission is returned when screen refuses to use its socket directory, usually because of its
+	// owner or mode.
+	ErrSocketDirPermission = errors.New("screen socket directory is not accessible")
+	// ErrUnparseable is returned when screen's output doesn't look like anything we know.
+	ErrUnparseable = screenparse.ErrUnparseable
+)
+
+// IsNotFound reports whether err means something doesn't exist: a screen, a window, a file on the Manager's host. Every
+// not-found error of this package satisfies it, be it os.ErrNotExist itself (as Get returns), wrapped, or an
+// ErrNotExist type *os.SyscallError carrying details (as commands on screens that went away return), which
+// os.IsNotExist doesn't see through.
+func IsNotFound(err error) bool {
+	var sysErr *os.SyscallError
+	if errors.As(err, &sysErr) && sysErr.Syscall == os.ErrNotExist.Error() {
+		return true
+	}
+	return os.IsNotExist(err) || errors.Is(err, os.ErrNotExist)
+}
+
+// CommandError is returned when a command run on the Manager's host fails, with everything it printed. Screen
+// commands that found no screen count as ErrNotExist, see IsNotFound.
+type CommandError struct {
+	Command []string // The command line, as given to the Runner
+	Stdout  []byte
+	Stderr  []byte
+	Err     error // What the Runner returned, usually an *exec.ExitError
+}
+
+func (e *CommandError) Error() string {
+	cmd, session := describeCommand(e.Command[0], e.Command[1:])
+	if session != "" {
+		cmd += " (screen " + session + ")"
+	}
+
+	text := strings.TrimSpace(string(e.Stderr))
+	if text == "" {
+		text = strings.TrimSpace(string(e.Stdout))
+	}
+	if text == "" {
+		text = e.Err.Error()
+	}
+	return cmd + ": " + text
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes a screen command that found no screen (because it went away in the meantime) match os.ErrNotExist.
+func (e *CommandError) Is(target error) bool {
+	notFound := []byte("No screen session found")
+	return target == os.ErrNotExist && (bytes.Contains(e.Stdout, notFound) || bytes.Contains(e.Stderr, notFound))
+}
+
+// listError classifies a failed or odd "screen -ls", returning nil if the output looks like a normal listing.
+func listError(out []byte, err error) error {
+	text := strings.TrimSpace(string(out))
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return ErrNotInstalled
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 127: // Shell or exec backend couldn't find it
+		return fmt.Errorf("%w: %s", ErrNotInstalled, text)
+	case err != nil && !errors.As(err, &exitErr): // Couldn't even run it
+		return err
+	case strings.Contains(text, "Permission denied"), strings.Contains(text, "must have mode"),
+		strings.Contains(text, "not the owner"):
+		return fmt.Errorf("%w: %s", ErrSocketDirPermission, text)
+	case strings.Contains(text, "No Sockets found in"), strings.Contains(text, "Socket in"),
+		strings.Contains(text, "Sockets in"):
+		// screen -ls exits with 1 on success, so its exit code doesn't tell us anything. Builds that word this
+		// differently end up as ErrUnparseable, which ListSessions double-checks with Manager.noSessions.
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnparseable, text)
+}
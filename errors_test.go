@@ -0,0 +1,109 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListError(t *testing.T) {
+	_, notFound := exec.Command("go-gnu-screen-no-such-binary").CombinedOutput()
+	_, exit1 := exec.Command("sh", "-c", "exit 1").CombinedOutput()
+	_, exit127 := exec.Command("sh", "-c", "exit 127").CombinedOutput()
+
+	tests := []struct {
+		out  string
+		err  error
+		want error
+	}{
+		{"There is a screen on:\n\t1.a\t(Detached)\n1 Socket in /run/screen/S-root.\n", exit1, nil},
+		{"No Sockets found in /run/screen/S-root.\n", exit1, nil},
+		{"", notFound, ErrNotInstalled},
+		{"sh: 1: screen: not found\n", exit127, ErrNotInstalled},
+		{"Directory '/run/screen' must have mode 777.\n", exit1, ErrSocketDirPermission},
+		{"Cannot make directory '/run/screen': Permission denied\n", exit1, ErrSocketDirPermission},
+		{"Segmentation fault\n", exit1, ErrUnparseable},
+	}
+
+	for _, test := range tests {
+		if got := listError([]byte(test.out), test.err); !errors.Is(got, test.want) || (got == nil) != (test.want == nil) {
+			t.Errorf("listError(%q, %v) = %v, want %v", test.out, test.err, got, test.want)
+		}
+	}
+}
+
+// TestListFixtures checks that "screen -ls" output captured from different versions of screen isn't mistaken for an
+// error, see screenparse/testdata/ls.
+func TestListFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("screenparse", "testdata", "ls", "*.txt"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatal("no fixtures", err)
+	}
+
+	for _, fixture := range fixtures {
+		b, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = listError(b, nil); err != nil {
+			t.Errorf("%s: %v", fixture, err)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{os.ErrNotExist, true},
+		{&os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}, true},
+		{fmt.Errorf("stuffing: %w", os.ErrNotExist), true},
+		{&os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("screen name cannot be empty")}, false},
+		{errors.New("No screen session found."), false},
+		{&CommandError{Command: []string{screenExec, "-S", "a", "-X", "stuff"}, Stdout: []byte("No screen session found.\n"), Err: errors.New("exit status 1")}, true},
+		{&CommandError{Command: []string{screenExec, "-S", "a", "-X", "foo"}, Stdout: []byte("unknown command 'foo'\n"), Err: errors.New("exit status 1")}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsNotFound(test.err); got != test.want {
+			t.Errorf("IsNotFound(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	r := &fakeRunner{
+		outputs: map[string]string{"mktemp -p /tmp": "mktemp: failed to create file: Permission denied\n"},
+		exits:   map[string]int{"mktemp -p /tmp": 1},
+	}
+	m := NewManagerWithRunner(r)
+
+	_, _, err := m.run(context.Background(), "mktemp", "-p", "/tmp")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("got %T, want *CommandError", err)
+	}
+	if want := []string{"mktemp", "-p", "/tmp"}; !reflect.DeepEqual(cmdErr.Command, want) {
+		t.Errorf("Command = %q, want %q", cmdErr.Command, want)
+	}
+	if want := "mktemp: mktemp: failed to create file: Permission denied"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Errorf("%v does not unwrap to the exit status", err)
+	}
+
+	// Failures report stderr, which used to be dropped
+	_, _, err = m.run(context.Background(), "ls", "-1A", "/nope")
+	if !errors.As(err, &cmdErr) || string(cmdErr.Stderr) != "unexpected command ls -1A /nope" {
+		t.Errorf("got %v, want the stderr kept", err)
+	}
+}
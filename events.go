@@ -0,0 +1,204 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// EventType is what happened to a screen, see Event.
+type EventType string
+
+const (
+	EventCreated  EventType = "created"  // Made by New, or seen for the first time by WatchSessions
+	EventDied     EventType = "died"     // Gone, as seen by WatchSessions
+	EventMatched  EventType = "matched"  // Output matched a pattern of WatchPatterns
+	EventHung     EventType = "hung"     // A Watchdog check failed
+	EventBell     EventType = "bell"     // A window rang the bell, as seen by WatchBells
+	EventAttached EventType = "attached" // Someone attached to a detached screen, as seen by WatchSessions
+	EventDetached EventType = "detached" // Everyone detached from a screen, as seen by WatchSessions
+)
+
+// Event is something that happened to a screen. Events are passed to the Manager's OnEvent.
+type Event struct {
+	Type   EventType `json:"type"`
+	Time   time.Time `json:"time"`
+	Screen Screen    `json:"screen"`
+
+	Pattern string `json:"pattern,omitempty"` // For EventMatched, the pattern that matched
+	Text    string `json:"text,omitempty"`    // For EventMatched, the line that matched
+	Window  *int   `json:"window,omitempty"`  // For EventBell, the number of the window that rang
+}
+
+// emit passes an event to OnEvent, if it's set.
+func (m *Manager) emit(e Event) {
+	if m.OnEvent != nil {
+		e.Time = time.Now()
+		m.OnEvent(e)
+	}
+}
+
+// WatchSessions lists the Manager's screens every interval until ctx is done, and emits EventCreated for screens that
+// appear and EventDied for screens that go away. Screens that exist when it starts don't count as created. It also
+// emits EventAttached and EventDetached when screens get attached or detached, i.e. to notify when a human leaves a
+// shared console.
+func (m *Manager) WatchSessions(ctx context.Context, interval time.Duration) error {
+	ctx, cancel, err := m.bind(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	known := map[string]Screen{}
+	attached := map[string]bool{}
+	first := true
+	for {
+		screens, states, err := m.listSessions()
+		if err != nil {
+			return err
+		}
+
+		current := make(map[string]Screen, len(screens))
+		currentAttached := make(map[string]bool, len(screens))
+		for i, s := range screens {
+			key := s.target()
+			current[key] = s
+			currentAttached[key] = states[i] == screenparse.StateAttached
+			_, ok := known[key]
+			switch {
+			case !ok && !first:
+				m.emit(Event{Type: EventCreated, Screen: s})
+			case !ok:
+			case states[i] == screenparse.StateAttached && !attached[key]:
+				m.emit(Event{Type: EventAttached, Screen: s})
+			case states[i] == screenparse.StateDetached && attached[key]:
+				m.emit(Event{Type: EventDetached, Screen: s})
+			}
+		}
+		for key, s := range known {
+			if _, ok := current[key]; !ok {
+				m.emit(Event{Type: EventDied, Screen: s})
+			}
+		}
+		known, attached, first = current, currentAttached, false
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WatchPatterns watches the screen's output until ctx is done, and emits EventMatched for every line matching one of
+// the patterns, i.e. `\[y/N\]` for a program waiting on a question. A line that doesn't end yet (like a prompt) is
+// matched too, but only once. Like Capture, this takes over the screen's logging while it runs.
+func (s Screen) WatchPatterns(ctx context.Context, patterns ...*regexp.Regexp) error {
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	c, err := s.Capture()
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		c.Close() // Unblocks Read
+	}()
+
+	lm := lineMatcher{match: func(line string) bool { return s.matchLine(line, patterns) }}
+	buf := make([]byte, 4096)
+	for {
+		n, err := c.Read(buf)
+		lm.feed(buf[:n])
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+// WatchBells checks the screen's windows every interval until ctx is done, and emits EventBell when one rings the
+// bell, i.e. a program printing BEL once it's done. Screen flags a window that rang until someone looks at it, so it
+// only counts again after that.
+func (s Screen) WatchBells(ctx context.Context, interval time.Duration) error {
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	rang := map[int]bool{}
+	for {
+		windows, err := s.Windows()
+		if err != nil {
+			return err
+		}
+
+		current := make(map[int]bool, len(windows))
+		for _, w := range windows {
+			if !strings.Contains(w.Flags, "!") {
+				continue
+			}
+			current[w.Number] = true
+			if !rang[w.Number] {
+				number := w.Number
+				s.m().emit(Event{Type: EventBell, Screen: s, Window: &number})
+			}
+		}
+		rang = current
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// matchLine emits EventMatched for every pattern matching a line of output. It reports whether any did.
+func (s Screen) matchLine(line string, patterns []*regexp.Regexp) bool {
+	matched := false
+	for _, p := range patterns {
+		if p.MatchString(line) {
+			s.m().emit(Event{Type: EventMatched, Screen: s, Pattern: p.String(), Text: line})
+			matched = true
+		}
+	}
+	return matched
+}
+
+// lineMatcher splits output into lines for match. An unfinished line is matched as it grows, until it matches once.
+type lineMatcher struct {
+	match func(line string) bool
+
+	line           []byte
+	partialMatched bool // Whether the unfinished line already matched
+}
+
+// feed adds output.
+func (lm *lineMatcher) feed(p []byte) {
+	for _, b := range p {
+		if b != '\n' {
+			lm.line = append(lm.line, b)
+			continue
+		}
+		if !lm.partialMatched {
+			lm.match(string(bytes.TrimRight(lm.line, "\r")))
+		}
+		lm.line, lm.partialMatched = lm.line[:0], false
+	}
+
+	if len(lm.line) > 0 && !lm.partialMatched {
+		lm.partialMatched = lm.match(string(bytes.TrimRight(lm.line, "\r")))
+	}
+}
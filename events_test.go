@@ -0,0 +1,140 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLineMatcher(t *testing.T) {
+	question := regexp.MustCompile(`\[y/N\]`)
+	var matched []string
+	lm := lineMatcher{match: func(line string) bool {
+		if question.MatchString(line) {
+			matched = append(matched, line)
+			return true
+		}
+		return false
+	}}
+
+	lm.feed([]byte("Installing...\r\nOverwrite config? [y"))
+	lm.feed([]byte("/N] "))
+	lm.feed([]byte("y")) // Typed answer, the line already matched
+	lm.feed([]byte("\r\nDone [y/N] in the same chunk\n"))
+
+	want := []string{"Overwrite config? [y/N] ", "Done [y/N] in the same chunk"}
+	if !reflect.DeepEqual(matched, want) {
+		t.Errorf("got %q, want %q", matched, want)
+	}
+}
+
+// listingsRunner answers "screen -ls" with one listing after the other, repeating the last one.
+type listingsRunner []string
+
+func (r *listingsRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out := (*r)[0]
+	if len(*r) > 1 {
+		*r = (*r)[1:]
+	}
+	return []byte(out), nil, nil
+}
+
+func TestWatchSessions(t *testing.T) {
+	m := NewManagerWithRunner(&listingsRunner{
+		"\t1.old\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+		"\t1.old\t(Detached)\n\t2.new\t(Detached)\n2 Sockets in /run/screen/S-root.\n",
+		"\t2.new\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	m.OnEvent = func(e Event) {
+		got = append(got, string(e.Type)+" "+e.Screen.Name)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+
+	if err := m.WatchSessions(ctx, time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if want := []string{"created new", "died old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// windowsRunner answers "-Q windows" with one reply after the other, repeating the last one.
+type windowsRunner struct {
+	*fakeRunner
+	replies []string
+}
+
+func (r *windowsRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if len(args) > 0 && args[len(args)-1] == "windows" {
+		out := r.replies[0]
+		if len(r.replies) > 1 {
+			r.replies = r.replies[1:]
+		}
+		return []byte(out), nil, nil
+	}
+	return r.fakeRunner.Run(ctx, name, args...)
+}
+
+func TestWatchBells(t *testing.T) {
+	m := NewManagerWithRunner(&windowsRunner{
+		fakeRunner: &fakeRunner{outputs: map[string]string{"screen -ls a+b": fakeList}},
+		replies: []string{
+			"0$ bash  1*$ make",
+			"0!$ bash  1*$ make",
+			"0!$ bash  1*$ make", // Still flagged, the same bell
+			"0$ bash  1*$ make",
+			"0!$ bash  1!*$ make",
+		},
+	})
+	m.Defaults.QueryTTL = -1
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []int
+	m.OnEvent = func(e Event) {
+		got = append(got, *e.Window)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+	if err = s.WatchBells(ctx, time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if want := []int{0, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got bells in windows %v, want %v", got, want)
+	}
+}
+
+func TestWatchSessionsDetach(t *testing.T) {
+	detached := "\t1.console\t(Detached)\n1 Socket in /run/screen/S-root.\n"
+	attached := "\t1.console\t(Attached)\n1 Socket in /run/screen/S-root.\n"
+	// Every listing is answered twice, the second time for reading the screen's start time
+	m := NewManagerWithRunner(&listingsRunner{detached, detached, attached, attached, attached, attached, detached})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	m.OnEvent = func(e Event) {
+		got = append(got, string(e.Type)+" "+e.Screen.Name)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+
+	if err := m.WatchSessions(ctx, time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if want := []string{"attached console", "detached console"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
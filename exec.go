@@ -0,0 +1,208 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecOptions sets up the environment of a command started with ExecWith, independently of the window's shell.
+type ExecOptions struct {
+	Dir string   // Working directory, empty means the screen's
+	Env []string // "KEY=value" variables added to the screen's environment
+}
+
+// ExecWith runs a command in the screen like Exec, in the directory and with the environment given by opts.
+func (s Screen) ExecWith(opts ExecOptions, fdpat FdPat, command string, args ...string) error {
+	command, args, err := opts.wrap(command, args)
+	if err != nil {
+		return err
+	}
+	return s.Exec(fdpat, command, args...)
+}
+
+// wrap returns a command line that runs command with args as opts asks. Everything is passed as separate arguments,
+// so nothing needs quoting.
+func (opts ExecOptions) wrap(command string, args []string) (string, []string, error) {
+	for _, kv := range opts.Env {
+		if i := strings.IndexByte(kv, '='); i <= 0 {
+			return "", nil, &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid environment variable " + kv)}
+		}
+	}
+
+	if len(opts.Env) > 0 {
+		args = append(append(append([]string{}, opts.Env...), command), args...)
+		command = "env"
+	}
+	if opts.Dir != "" {
+		args = append([]string{"-c", `cd "$0" && exec "$@"`, opts.Dir, command}, args...)
+		command = "/bin/sh"
+	}
+
+	return command, args, nil
+}
+
+// ExecProcess is a command started in a screen with ExecStart.
+type ExecProcess struct {
+	PID int // PID of the process, which is a child of the screen
+
+	m      *Manager
+	status string // File the exit code gets written to
+}
+
+// ExecStart runs a command in the screen like Exec, and finds the process it started, so it can be waited for.
+func (s Screen) ExecStart(fdpat FdPat, command string, args ...string) (*ExecProcess, error) {
+	if s.Process == nil {
+		return nil, os.ErrNotExist
+	}
+
+	status, err := s.m().tempFile()
+	if err != nil {
+		return nil, err
+	}
+	s.m().remove(status) // Only the wrapper creates it, so a leftover empty file can't be mistaken for a result
+
+	before, err := s.m().childPIDs(s.Process.Pid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Screen reaps its children itself, so the exit code is gone from /proc by the time we look; have a shell keep it
+	shellArgs := append([]string{"-c", `"$@"; echo $? > "$0"`, status, command}, args...)
+	if err = s.Exec(fdpat, "/bin/sh", shellArgs...); err != nil {
+		return nil, err
+	}
+
+	after, err := s.m().childPIDs(s.Process.Pid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new child is the one whose command line mentions our status file
+	existed := make(map[int]bool)
+	for _, pid := range before {
+		existed[pid] = true
+	}
+	for _, pid := range after {
+		if existed[pid] {
+			continue
+		}
+		cmdline, err := s.m().readFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+		if err == nil && strings.Contains(string(cmdline), status) {
+			return &ExecProcess{PID: pid, m: s.m(), status: status}, nil
+		}
+	}
+
+	// Either it exited already, or screen hasn't forked it yet; the status file settles which
+	return &ExecProcess{PID: -1, m: s.m(), status: status}, nil
+}
+
+// ExecWait runs a command in the screen like Exec, and waits for it to exit. See ExecProcess.Wait.
+func (s Screen) ExecWait(ctx context.Context, fdpat FdPat, command string, args ...string) (int, error) {
+	p, err := s.ExecStart(fdpat, command, args...)
+	if err != nil {
+		return -1, err
+	}
+	return p.Wait(ctx)
+}
+
+// Wait blocks until the process exits, and returns its exit code. If ctx is done first, the process keeps running.
+func (p *ExecProcess) Wait(ctx context.Context) (int, error) {
+	ctx, cancel, err := p.m.bind(ctx, "")
+	if err != nil {
+		return -1, err
+	}
+	defer cancel()
+
+	for {
+		if p.PID <= 0 || p.m.stat("/proc/"+strconv.Itoa(p.PID)) != nil {
+			if b, err := p.m.readFile(p.status); err == nil && len(b) > 0 {
+				p.m.remove(p.status)
+				return strconv.Atoi(strings.TrimSpace(string(b)))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-time.After(p.m.outputPoll()):
+		}
+	}
+}
+
+// ExecPipe runs a command in the screen like Exec, but its stdout comes back to us instead of going to the window. The
+// returned reader hits EOF once the command exits, and must be closed afterwards. Stderr still goes to the window,
+// and user input keeps going to the window's application. Closing the reader, or canceling ctx, stops reading, but
+// doesn't stop the command.
+func (s Screen) ExecPipe(ctx context.Context, command string, args ...string) (io.ReadCloser, error) {
+	fifo, err := s.m().fifo()
+	if err != nil {
+		return nil, err
+	}
+
+	// cat waits for the command to open the pipe, and exits once it closes it
+	p := &pipeReader{m: s.m(), fifo: fifo, closed: make(chan struct{})}
+	if p.cmd, err = s.m().command(context.Background(), "cat", fifo); err == nil {
+		if p.r, err = p.cmd.StdoutPipe(); err == nil {
+			err = p.cmd.Start()
+		}
+	}
+	if err != nil {
+		s.m().remove(fifo)
+		return nil, err
+	}
+
+	// The command's stdout is redirected by a shell, since fdpats can't point anywhere but screen and the application
+	shellArgs := append([]string{"-c", `exec "$@" > "$0"`, fifo, command}, args...)
+	if err = s.Exec(FdPat{Pipe: true}, "/bin/sh", shellArgs...); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	go func() {
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			p.Close()
+		case <-p.closed:
+		}
+	}()
+
+	return p, nil
+}
+
+// pipeReader reads a named pipe on a Manager's host, and cleans it up when closed.
+type pipeReader struct {
+	m    *Manager
+	fifo string
+	cmd  *exec.Cmd
+	r    io.ReadCloser
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (p *pipeReader) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+func (p *pipeReader) Close() error {
+	p.closeOnce.Do(func() {
+		p.cmd.Process.Kill()
+		p.cmd.Wait()
+		p.m.remove(p.fifo)
+		close(p.closed)
+	})
+	return nil
+}
@@ -0,0 +1,35 @@
+package screen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExecOptionsWrap(t *testing.T) {
+	tests := []struct {
+		opts     ExecOptions
+		wantCmd  string
+		wantArgs []string
+	}{
+		{ExecOptions{}, "make", []string{"-j", "4"}},
+		{ExecOptions{Env: []string{"CC=clang"}}, "env", []string{"CC=clang", "make", "-j", "4"}},
+		{
+			ExecOptions{Dir: "/src/my project", Env: []string{"CC=clang"}}, "/bin/sh",
+			[]string{"-c", `cd "$0" && exec "$@"`, "/src/my project", "env", "CC=clang", "make", "-j", "4"},
+		},
+	}
+
+	for _, test := range tests {
+		cmd, args, err := test.opts.wrap("make", []string{"-j", "4"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd != test.wantCmd || !reflect.DeepEqual(args, test.wantArgs) {
+			t.Errorf("%+v wrapped to %q %q, want %q %q", test.opts, cmd, args, test.wantCmd, test.wantArgs)
+		}
+	}
+
+	if _, _, err := (ExecOptions{Env: []string{"=oops"}}).wrap("make", nil); err == nil {
+		t.Error("expected an error for an invalid variable")
+	}
+}
@@ -0,0 +1,52 @@
+package screen
+
+import (
+	"path"
+	"sort"
+	"sync"
+)
+
+// FanOutResult is what a FanOut did to one screen.
+type FanOutResult struct {
+	Screen Screen
+	Err    error
+}
+
+// FanOut calls fn concurrently for every screen whose name matches the glob pattern (see path.Match), i.e. to stuff
+// the same command into "deploy-*". The results are sorted by name. Only a bad pattern or failing to list the screens
+// is returned as an error, what fn returns is in the results.
+func FanOut(pattern string, fn func(s Screen) error) ([]FanOutResult, error) {
+	return local.FanOut(pattern, fn)
+}
+
+// FanOut calls fn for every matching screen on the Manager's host. See FanOut.
+func (m *Manager) FanOut(pattern string, fn func(s Screen) error) ([]FanOutResult, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	screens, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []FanOutResult
+	for _, s := range screens {
+		if ok, _ := path.Match(pattern, s.Name); ok {
+			res = append(res, FanOutResult{Screen: s})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Screen.Name < res[j].Screen.Name })
+
+	var wg sync.WaitGroup
+	for i := range res {
+		wg.Add(1)
+		go func(r *FanOutResult) {
+			defer wg.Done()
+			r.Err = fn(r.Screen)
+		}(&res[i])
+	}
+	wg.Wait()
+
+	return res, nil
+}
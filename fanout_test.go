@@ -0,0 +1,29 @@
+package screen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFanOut(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+		"screen -ls": "\t3.deploy-us\t(Detached)\n\t1.build\t(Detached)\n\t2.deploy-eu\t(Detached)\n",
+	}})
+
+	res, err := m.FanOut("deploy-*", func(s Screen) error {
+		if s.Name == "deploy-us" {
+			return errors.New("nope")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Screen.Name != "deploy-eu" || res[0].Err != nil || res[1].Err == nil {
+		t.Errorf("got %+v", res)
+	}
+
+	if _, err = m.FanOut("[", func(Screen) error { return nil }); err == nil {
+		t.Error("accepted a bad pattern")
+	}
+}
@@ -0,0 +1,78 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FdConn is what one of an exec'd command's file descriptors is connected to. See the "exec" section of "man screen".
+type FdConn byte
+
+const (
+	Unset FdConn = 0   // Screen's default, which is the same as Dot
+	Dot   FdConn = '.' // Screen, i.e. the window
+	Bang  FdConn = '!' // The window's application process
+	Colon FdConn = ':' // Both
+)
+
+// FdPat describes how the standard file descriptors of a command started with Exec are connected. The zero value
+// leaves them all to screen.
+type FdPat struct {
+	Stdin, Stdout, Stderr FdConn
+	// Pipe keeps user input away from the command, even when its stdin isn't connected to the application process.
+	Pipe bool
+	// Raw, if set, is used as the pattern as-is, ignoring every other field.
+	Raw string
+}
+
+// RawFdPat returns an FdPat for a pattern in screen's own syntax, i.e. "!..|".
+func RawFdPat(pattern string) FdPat {
+	return FdPat{Raw: pattern}
+}
+
+var rawFdPatRegexp = regexp.MustCompile(`^[.!:]{0,3}\|?$`)
+
+// String returns the pattern in screen's syntax. Unset file descriptors at the end are left out.
+func (p FdPat) String() string {
+	if p.Raw != "" {
+		return p.Raw
+	}
+
+	conns := []FdConn{p.Stdin, p.Stdout, p.Stderr}
+	for len(conns) > 0 && conns[len(conns)-1] == Unset {
+		conns = conns[:len(conns)-1]
+	}
+
+	var b strings.Builder
+	for _, c := range conns {
+		if c == Unset {
+			c = Dot
+		}
+		b.WriteByte(byte(c))
+	}
+	if p.Pipe {
+		b.WriteByte('|')
+	}
+	return b.String()
+}
+
+// Validate checks that the pattern is one screen accepts.
+func (p FdPat) Validate() error {
+	if p.Raw != "" {
+		if !rawFdPatRegexp.MatchString(p.Raw) {
+			return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid fdpat " + p.Raw)}
+		}
+		return nil
+	}
+
+	for _, c := range []FdConn{p.Stdin, p.Stdout, p.Stderr} {
+		switch c {
+		case Unset, Dot, Bang, Colon:
+		default:
+			return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid fdpat connection " + string(c))}
+		}
+	}
+	return nil
+}
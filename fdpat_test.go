@@ -0,0 +1,29 @@
+package screen
+
+import "testing"
+
+func TestFdPat(t *testing.T) {
+	tests := []struct {
+		pat   FdPat
+		want  string
+		valid bool
+	}{
+		{FdPat{}, "", true},
+		{FdPat{Stdin: Bang}, "!", true},
+		{FdPat{Stdout: Bang, Pipe: true}, ".!|", true},
+		{FdPat{Stdin: Colon, Stdout: Dot, Stderr: Bang}, ":.!", true},
+		{FdPat{Stdin: 'x'}, "x", false},
+		{RawFdPat("!..|"), "!..|", true},
+		{RawFdPat("/bin/sh"), "/bin/sh", false},
+		{RawFdPat("...."), "....", false},
+	}
+
+	for _, test := range tests {
+		if got := test.pat.String(); got != test.want {
+			t.Errorf("%+v.String() = %q, want %q", test.pat, got, test.want)
+		}
+		if err := test.pat.Validate(); (err == nil) != test.valid {
+			t.Errorf("%+v.Validate() = %v, want valid %v", test.pat, err, test.valid)
+		}
+	}
+}
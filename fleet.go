@@ -0,0 +1,203 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fleet manages the screens of several hosts at once, each through its own Manager. Screens are named "<host>/<name>"
+// across the fleet, i.e. "build-2/buildbot".
+type Fleet struct {
+	mutex sync.RWMutex
+	hosts map[string]*Manager
+}
+
+// FleetScreen is a screen on one of a Fleet's hosts.
+type FleetScreen struct {
+	Host string
+	Screen
+}
+
+// ID returns the screen's name across the fleet, "<host>/<name>".
+func (fs FleetScreen) ID() string {
+	return fs.Host + "/" + fs.Name
+}
+
+// FleetError collects the errors of an operation on several hosts or screens, keyed by host or screen ID.
+type FleetError map[string]error
+
+func (e FleetError) Error() string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, len(keys))
+	for i, key := range keys {
+		msgs[i] = key + ": " + e[key].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// NewFleet returns an empty Fleet.
+func NewFleet() *Fleet {
+	return &Fleet{hosts: make(map[string]*Manager)}
+}
+
+// Add adds a host to the fleet, or replaces the Manager of one that's already there. Host names can't be empty or
+// contain a "/".
+func (f *Fleet) Add(host string, m *Manager) error {
+	if host == "" || strings.Contains(host, "/") {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid host name: " + host)}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.hosts[host] = m
+	return nil
+}
+
+// Remove removes a host from the fleet. Its screens keep running.
+func (f *Fleet) Remove(host string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.hosts, host)
+}
+
+// Hosts returns the names of the fleet's hosts, sorted.
+func (f *Fleet) Hosts() []string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	res := make([]string, 0, len(f.hosts))
+	for host := range f.hosts {
+		res = append(res, host)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Manager returns the Manager of a host, or nil if the host isn't part of the fleet.
+func (f *Fleet) Manager(host string) *Manager {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.hosts[host]
+}
+
+// New creates a screen on a host, given its ID "<host>/<name>". See Manager.New.
+func (f *Fleet) New(ctx context.Context, id string, shell ...string) (FleetScreen, error) {
+	m, host, name, err := f.split(id)
+	if err != nil {
+		return FleetScreen{}, err
+	}
+	s, err := m.New(ctx, name, shell...)
+	return FleetScreen{Host: host, Screen: s}, err
+}
+
+// Get retrieves a screen given its ID "<host>/<name>". See Manager.Get.
+func (f *Fleet) Get(id string) (FleetScreen, error) {
+	m, host, name, err := f.split(id)
+	if err != nil {
+		return FleetScreen{}, err
+	}
+	s, err := m.Get(name)
+	return FleetScreen{Host: host, Screen: s}, err
+}
+
+// ListSessions lists the screens of every host at once, sorted by ID. Hosts that fail are left out, and their errors
+// are returned as a FleetError keyed by host.
+func (f *Fleet) ListSessions() ([]FleetScreen, error) {
+	f.mutex.RLock()
+	hosts := make(map[string]*Manager, len(f.hosts))
+	for host, m := range f.hosts {
+		hosts[host] = m
+	}
+	f.mutex.RUnlock()
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		res   []FleetScreen
+		errs  = FleetError{}
+	)
+	for host, m := range hosts {
+		wg.Add(1)
+		go func(host string, m *Manager) {
+			defer wg.Done()
+			screens, err := m.ListSessions()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs[host] = err
+				return
+			}
+			for _, s := range screens {
+				res = append(res, FleetScreen{Host: host, Screen: s})
+			}
+		}(host, m)
+	}
+	wg.Wait()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID() < res[j].ID() })
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
+// GetAll returns the screens of every host that could be reached. See ListSessions.
+func (f *Fleet) GetAll() []FleetScreen {
+	res, _ := f.ListSessions()
+	return res
+}
+
+// Broadcast calls fn for every screen of the fleet concurrently, i.e. to stuff the same command into all of them. The
+// errors of fn and of hosts that couldn't be listed are returned as a FleetError, keyed by screen ID and host.
+func (f *Fleet) Broadcast(fn func(s FleetScreen) error) error {
+	screens, err := f.ListSessions()
+	errs := FleetError{}
+	errors.As(err, &errs)
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	for _, s := range screens {
+		wg.Add(1)
+		go func(s FleetScreen) {
+			defer wg.Done()
+			if err := fn(s); err != nil {
+				mutex.Lock()
+				errs[s.ID()] = err
+				mutex.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// split splits a screen ID into its host's Manager and the screen's name.
+func (f *Fleet) split(id string) (m *Manager, host, name string, err error) {
+	i := strings.Index(id, "/")
+	if i < 0 {
+		err = &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("screen ID must look like <host>/<name>: " + id)}
+		return
+	}
+
+	host, name = id[:i], id[i+1:]
+	if m = f.Manager(host); m == nil {
+		err = &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("host not in fleet: " + host)}
+	}
+	return
+}
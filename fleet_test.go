@@ -0,0 +1,60 @@
+package screen
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFleet(t *testing.T) {
+	f := NewFleet()
+	f.Add("a", NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+		"screen -ls":          "\t1.build\t(Detached)\n\t2.deploy\t(Detached)\n",
+		"screen -ls buildbot": "\t3.buildbot\t(Detached)\n",
+	}}))
+	f.Add("b", NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": "\t4.build\t(Attached)\n"}}))
+	f.Add("down", NewManagerWithRunner(&fakeRunner{}))
+
+	if err := f.Add("a/b", NewManager()); err == nil {
+		t.Error("accepted a host name with a slash")
+	}
+
+	screens, err := f.ListSessions()
+	var ids []string
+	for _, s := range screens {
+		ids = append(ids, s.ID())
+	}
+	if want := []string{"a/build", "a/deploy", "b/build"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+	var fleetErr FleetError
+	if !errors.As(err, &fleetErr) || len(fleetErr) != 1 || fleetErr["down"] == nil {
+		t.Errorf("got %v, want an error for host down only", err)
+	}
+
+	if s, err := f.Get("a/buildbot"); err != nil || s.Process.Pid != 3 {
+		t.Errorf("got %+v, %v", s, err)
+	}
+	if _, err := f.Get("nowhere/buildbot"); err == nil {
+		t.Error("got a screen from a host that isn't in the fleet")
+	}
+
+	var mutex sync.Mutex
+	var visited []string
+	err = f.Broadcast(func(s FleetScreen) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		visited = append(visited, s.ID())
+		if s.Host == "b" {
+			return errors.New("nope")
+		}
+		return nil
+	})
+	if len(visited) != 3 {
+		t.Errorf("visited %v", visited)
+	}
+	if !errors.As(err, &fleetErr) || len(fleetErr) != 2 || fleetErr["b/build"] == nil {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package screen
+
+import (
+	"sync"
+)
+
+// NamedHandle refers to a screen by name instead of by process. A Screen stops working once its session ends, but a
+// NamedHandle picks up whichever session has the name at the time it's used, so supervisors holding one keep
+// working after the session is recreated.
+type NamedHandle struct {
+	Name string
+
+	m      *Manager
+	mu     sync.Mutex
+	cached *Screen
+}
+
+// Handle returns a NamedHandle for the local screen with the given name. The screen doesn't need to exist yet.
+func Handle(name string) *NamedHandle {
+	return local.Handle(name)
+}
+
+// Handle returns a NamedHandle for the screen with the given name on the Manager's host. See Handle.
+func (m *Manager) Handle(name string) *NamedHandle {
+	return &NamedHandle{Name: name, m: m}
+}
+
+// Screen returns the current screen with the handle's name. If there is none, ErrNotExist type is returned.
+func (h *NamedHandle) Screen() (Screen, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cached != nil && h.cached.isOnline() {
+		return *h.cached, nil
+	}
+
+	s, err := h.m.Get(h.Name)
+	if err != nil {
+		h.cached = nil
+		return Screen{}, err
+	}
+	h.cached = &s
+	return s, nil
+}
+
+// Do calls fn with the current screen. If fn fails because that screen went away in the meantime, it's called once
+// more with its replacement, if there is one.
+func (h *NamedHandle) Do(fn func(s Screen) error) error {
+	s, err := h.Screen()
+	if err != nil {
+		return err
+	}
+	if err = fn(s); err == nil || !IsNotFound(err) {
+		return err
+	}
+
+	if s, err = h.Screen(); err != nil {
+		return err
+	}
+	return fn(s)
+}
@@ -0,0 +1,71 @@
+package screen
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// Health sums up whether screen works on a Manager's host, see Manager.HealthCheck.
+type Health struct {
+	Installed    bool     `json:"installed"`           // The screen binary was found
+	Version      string   `json:"version,omitempty"`   // Its version, i.e. "4.9.0"
+	SocketDir    string   `json:"socketDir,omitempty"` // Where the current user's sockets are, or will be
+	SocketDirOK  bool     `json:"socketDirOk"`         // screen accepts the socket directory, or can create it
+	Reachable    bool     `json:"reachable"`           // "screen -ls" could list the sessions
+	Sessions     int      `json:"sessions"`            // Live sessions
+	StaleSockets int      `json:"staleSockets"`        // Sockets of dead sessions, which "screen -wipe" removes
+	Problems     []string `json:"problems,omitempty"`  // What's wrong, with how to fix it where that's known
+}
+
+// Healthy reports whether screens can be made and reached. Stale sockets don't count, they're only clutter.
+func (h Health) Healthy() bool {
+	return h.Installed && h.SocketDirOK && h.Reachable
+}
+
+// HealthCheck checks screen on the local machine, see Manager.HealthCheck.
+func HealthCheck(ctx context.Context) Health {
+	return local.HealthCheck(ctx)
+}
+
+// HealthCheck checks that screen is installed on the Manager's host, that its socket directory is usable, and that
+// the sessions can be listed, for a service's readiness probe. It never fails itself, everything that's wrong ends up
+// in Problems.
+func (m *Manager) HealthCheck(ctx context.Context) (h Health) {
+	v, err := m.Version()
+	if err != nil {
+		h.Problems = append(h.Problems, "screen: "+err.Error())
+		return
+	}
+	h.Installed, h.Version = true, v.String()
+
+	stdout, stderr, err := m.run(ctx, "screen", "-ls")
+	out := append(stdout, stderr...)
+	entries := screenparse.ParseList(string(out))
+	if err = listError(out, err); err != nil && len(entries) == 0 &&
+		!(errors.Is(err, ErrUnparseable) && m.noSessions(string(out))) {
+		h.Problems = append(h.Problems, "listing sessions: "+err.Error())
+	} else {
+		h.Reachable = true
+	}
+	for _, e := range entries {
+		if e.Status.Dead {
+			h.StaleSockets++
+		} else {
+			h.Sessions++
+		}
+	}
+
+	h.SocketDir, _ = m.findSocketDir(string(out))
+	if fix, err := m.CheckSocketDir(); err != nil {
+		problem := "socket directory: " + err.Error()
+		if fix != "" {
+			problem += ", fix with: " + fix
+		}
+		h.Problems = append(h.Problems, problem)
+	} else {
+		h.SocketDirOK = true
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package screen
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"/usr/bin/screen -v": "Screen version 4.09.00 (GNU) 30-Jan-22\n",
+		"id -u":              "1000\n",
+		"screen -ls": "There are screens on:\n" +
+			"\t4242.a+b\t(Attached)\n" +
+			"\t4243.gone\t(Dead ???)\n" +
+			"2 Sockets in /run/screen/S-root.\n",
+		"stat -c %u %a /run/screen/S-root": "1000 755\n",
+	}, exits: map[string]int{"/usr/bin/screen -v": 1, "screen -ls": 1}}
+
+	h := NewManagerWithRunner(r).HealthCheck(context.Background())
+	want := Health{Installed: true, Version: "4.9.0", SocketDir: "/run/screen/S-root", Reachable: true, Sessions: 1, StaleSockets: 1}
+	if h.Installed != want.Installed || h.Version != want.Version || h.SocketDir != want.SocketDir ||
+		h.Reachable != want.Reachable || h.Sessions != want.Sessions || h.StaleSockets != want.StaleSockets {
+		t.Errorf("got %+v, want %+v", h, want)
+	}
+	if h.SocketDirOK || h.Healthy() || len(h.Problems) != 1 {
+		t.Errorf("got %+v, want the socket directory's mode as a problem", h)
+	}
+
+	// Without screen, there's nothing else to check
+	h = NewManagerWithRunner(&fakeRunner{}).HealthCheck(context.Background())
+	if h.Installed || h.Healthy() || len(h.Problems) != 1 {
+		t.Errorf("got %+v without screen", h)
+	}
+}
@@ -0,0 +1,18 @@
+package screen
+
+import "encoding/json"
+
+// screenJSON is how a Screen looks in JSON.
+type screenJSON struct {
+	Name string `json:"name"`
+	PID  int    `json:"pid,omitempty"`
+}
+
+// MarshalJSON encodes the screen as {"name": ..., "pid": ...}, leaving out its mutex and Manager.
+func (s Screen) MarshalJSON() ([]byte, error) {
+	v := screenJSON{Name: s.Name}
+	if s.Process != nil {
+		v.PID = s.Process.Pid
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,17 @@
+package screen
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestScreenMarshalJSON(t *testing.T) {
+	b, err := json.Marshal([]Screen{{Name: "a", Process: &os.Process{Pid: 42}}, {Name: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"name":"a","pid":42},{"name":"b"}]`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
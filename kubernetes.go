@@ -0,0 +1,23 @@
+package screen
+
+const kubectlExec = "kubectl"
+
+// NewKubernetesManager returns a Manager that runs every screen command inside a pod, through the pod's exec
+// subresource via "kubectl exec". Leave namespace empty to use the current context's namespace, and container empty
+// to use the pod's default container. kubectl must be configured (i.e. KUBECONFIG) for the target cluster.
+func NewKubernetesManager(namespace, pod, container string) *Manager {
+	var target []string
+	if namespace != "" {
+		target = append(target, "-n", namespace)
+	}
+	target = append(target, pod)
+	if container != "" {
+		target = append(target, "-c", container)
+	}
+	target = append(target, "--")
+
+	return NewManagerWithRunner(ExecRunner{
+		Prefix:    append([]string{kubectlExec, "exec"}, target...),
+		TTYPrefix: append([]string{kubectlExec, "exec", "-it"}, target...),
+	})
+}
@@ -0,0 +1,17 @@
+package screen
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestKubernetesManagerCommand(t *testing.T) {
+	m := NewKubernetesManager("debug", "api-0", "app")
+	cmd, _ := m.command(context.Background(), screenExec, "-ls")
+
+	want := []string{"kubectl", "exec", "-n", "debug", "api-0", "-c", "app", "--", screenExec, "-ls"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("got %q, want %q", cmd.Args, want)
+	}
+}
@@ -0,0 +1,225 @@
+package screen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrLeaseLost is returned by Lease.Err when the lease expired before it could be renewed, and someone else took it.
+var ErrLeaseLost = errors.New("lease lost")
+
+// Lease is exclusive ownership of a screen among the processes of a host that coordinate through Screen.Lease. It's
+// advisory: screen itself knows nothing about it.
+type Lease struct {
+	Owner string // Unique to this lease, i.e. "build-host:1234:9f86d081"
+
+	m    *Manager
+	path string
+	ttl  time.Duration
+
+	stop   chan struct{}
+	done   chan struct{}
+	lost   chan struct{}
+	unhold func()
+	err    error // ErrLeaseLost once lost is closed
+
+	releaseOnce sync.Once
+	releaseErr  error
+}
+
+// leaseRecord is the content of a lease file.
+type leaseRecord struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// Lease waits until the screen isn't leased by anyone else, or ctx is done, and takes the lease for ttl. The lease is
+// renewed in the background until Release is called, or the Manager is closed. If renewing fails long enough for
+// the lease to expire, another process may steal it, which closes Lost.
+//
+// The lease is a file next to the screen's socket, which is updated under an advisory flock, so processes crashing
+// or hanging while holding it can't keep the screen forever. Leases are only supported by local Managers.
+func (s Screen) Lease(ctx context.Context, ttl time.Duration) (*Lease, error) {
+	// Renewed every third of ttl, which has to be something
+	if ttl/3 <= 0 {
+		return nil, &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid lease ttl " + ttl.String())}
+	}
+	if !s.m().isLocal() {
+		return nil, &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("leases need a local Manager")}
+	}
+
+	status, err := s.Status()
+	if err != nil {
+		return nil, err
+	}
+	if status.SocketPath == "" {
+		return nil, &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("socket of screen not found")}
+	}
+
+	// Screen ignores hidden files in its socket directory
+	return s.m().lease(ctx, s.Name, path.Join(path.Dir(status.SocketPath), "."+path.Base(status.SocketPath)+".lease"), ttl)
+}
+
+// lease takes the lease in the given file, see Screen.Lease.
+func (m *Manager) lease(ctx context.Context, screen, file string, ttl time.Duration) (*Lease, error) {
+	owner, err := leaseOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		holder, err := takeLease(file, owner, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if holder == owner {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.outputPoll()):
+		}
+	}
+
+	l := &Lease{
+		Owner: owner,
+		m:     m,
+		path:  file,
+		ttl:   ttl,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+		lost:  make(chan struct{}),
+	}
+	if l.unhold, err = m.hold(screen, func() { l.Release() }); err != nil {
+		l.Release()
+		return nil, err
+	}
+	go l.renew()
+	return l, nil
+}
+
+// renew renews the lease every third of its ttl, until it's released or lost.
+func (l *Lease) renew() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// Failing to renew is retried, until someone else has taken the lease
+		if holder, err := takeLease(l.path, l.Owner, l.ttl); err == nil && holder != l.Owner {
+			l.err = ErrLeaseLost
+			close(l.lost)
+			return
+		}
+	}
+}
+
+// Lost is closed when the lease was taken by someone else, since it couldn't be renewed in time.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Err returns ErrLeaseLost once Lost is closed, nil before.
+func (l *Lease) Err() error {
+	select {
+	case <-l.lost:
+		return l.err
+	default:
+		return nil
+	}
+}
+
+// Release stops renewing the lease, and gives it up unless someone else has taken it already.
+func (l *Lease) Release() error {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+		if l.unhold != nil {
+			l.unhold()
+		}
+		if l.Err() == nil {
+			l.releaseErr = updateLease(l.path, func(r *leaseRecord) {
+				if r.Owner == l.Owner {
+					*r = leaseRecord{}
+				}
+			})
+		}
+	})
+	return l.releaseErr
+}
+
+// takeLease takes or renews the lease in file for owner, unless someone else holds it and it hasn't expired. It
+// returns who holds the lease afterwards.
+func takeLease(file, owner string, ttl time.Duration) (holder string, err error) {
+	err = updateLease(file, func(r *leaseRecord) {
+		now := time.Now()
+		if r.Owner == "" || r.Owner == owner || !now.Before(r.Expires) {
+			*r = leaseRecord{Owner: owner, Expires: now.Add(ttl)}
+		}
+		holder = r.Owner
+	})
+	return
+}
+
+// updateLease changes the record in a lease file while holding its lock. Unreadable files count as free.
+func updateLease(file string, update func(r *leaseRecord)) error {
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // Also unlocks
+
+	if err = lockFile(f); err != nil {
+		return err
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	var r leaseRecord
+	json.Unmarshal(b, &r)
+	before := r
+	if update(&r); r == before {
+		return nil
+	}
+
+	if r.Owner == "" {
+		return f.Truncate(0)
+	}
+	if b, err = json.Marshal(r); err != nil {
+		return err
+	}
+	if err = f.Truncate(0); err != nil {
+		return err
+	}
+	_, err = f.WriteAt(b, 0)
+	return err
+}
+
+// leaseOwner returns a new unique owner for a lease.
+func leaseOwner() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	host, _ := os.Hostname()
+	return host + ":" + strconv.Itoa(os.Getpid()) + ":" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,101 @@
+package screen
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLease(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{})
+	m.Defaults.OutputPoll = time.Millisecond * 10
+	file := filepath.Join(t.TempDir(), ".42.build.lease")
+
+	l1, err := m.lease(context.Background(), "build", file, time.Millisecond*150)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Renewed past its ttl, so nobody else gets it
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*300)
+	defer cancel()
+	if _, err = m.lease(ctx, "build", file, time.Second); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		released <- l1.Release()
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	l2, err := m.lease(ctx, "build", file, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-released; err != nil {
+		t.Fatal(err)
+	}
+	if l2.Owner == l1.Owner || l1.Err() != nil {
+		t.Errorf("got owners %q and %q, error %v", l1.Owner, l2.Owner, l1.Err())
+	}
+
+	// Closing the Manager releases it
+	m.Close()
+	if b, _ := os.ReadFile(file); len(b) != 0 {
+		t.Errorf("lease file still holds %s", b)
+	}
+}
+
+func TestLeaseTTL(t *testing.T) {
+	r := &fakeRunner{}
+	s := Screen{Name: "build", manager: NewManagerWithRunner(r)}
+	for _, ttl := range []time.Duration{0, -time.Second, 2} {
+		if _, err := s.Lease(context.Background(), ttl); err == nil {
+			t.Errorf("%v: leased", ttl)
+		}
+	}
+	if len(r.ran) != 0 {
+		t.Errorf("ran %q", r.ran)
+	}
+}
+
+func TestLeaseSteal(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{})
+	file := filepath.Join(t.TempDir(), ".42.build.lease")
+	expired, _ := json.Marshal(leaseRecord{Owner: "crashed", Expires: time.Now().Add(-time.Second)})
+	if err := os.WriteFile(file, expired, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	l, err := m.lease(ctx, "build", file, time.Millisecond*60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release()
+
+	// Someone else takes it over, as if we hadn't renewed in time
+	taken, _ := json.Marshal(leaseRecord{Owner: "thief", Expires: time.Now().Add(time.Hour)})
+	if err = os.WriteFile(file, taken, 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-l.Lost():
+		if l.Err() != ErrLeaseLost {
+			t.Errorf("got %v", l.Err())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("lease wasn't lost")
+	}
+
+	l.Release()
+	if b, _ := os.ReadFile(file); string(b) != string(taken) {
+		t.Errorf("releasing a lost lease changed it to %s", b)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package screen
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile isn't supported, leases need a local Manager and those run screen through WSL on windows.
+func lockFile(f *os.File) error {
+	return errors.New("leases aren't supported on windows")
+}
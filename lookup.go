@@ -0,0 +1,64 @@
+package screen
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// GetPrefix returns the screens whose names start with prefix, sorted like ListSessions. Unlike Get, which only
+// matches whole names, GetPrefix("build") finds "build" and "build-2", the way "screen -r build" would pick one.
+func GetPrefix(prefix string) ([]Screen, error) {
+	return local.GetPrefix(prefix)
+}
+
+// GetPrefix returns the screens on the Manager's host whose names start with prefix. See GetPrefix.
+func (m *Manager) GetPrefix(prefix string) (res []Screen, err error) {
+	screens, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range screens {
+		if strings.HasPrefix(s.Name, prefix) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+// Find returns the screens whose names match the glob pattern (see path.Match), i.e. "build-*". A bad pattern matches
+// nothing, like a failure to list the screens; use FanOut to tell these apart.
+func Find(pattern string) []Screen {
+	return local.Find(pattern)
+}
+
+// FindRegexp returns the screens whose names match re, i.e. regexp.MustCompile(`^build-\d+$`).
+func FindRegexp(re *regexp.Regexp) []Screen {
+	return local.FindRegexp(re)
+}
+
+// Find returns the screens on the Manager's host whose names match the glob pattern. See Find.
+func (m *Manager) Find(pattern string) []Screen {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil
+	}
+	return m.filter(func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
+
+// FindRegexp returns the screens on the Manager's host whose names match re. See FindRegexp.
+func (m *Manager) FindRegexp(re *regexp.Regexp) []Screen {
+	return m.filter(re.MatchString)
+}
+
+// filter returns the screens on the Manager's host whose names match.
+func (m *Manager) filter(match func(name string) bool) (res []Screen) {
+	for _, s := range m.GetAll() {
+		if match(s.Name) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
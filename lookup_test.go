@@ -0,0 +1,39 @@
+package screen
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestGetExact(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+		"screen -ls deploy europe-west": fakeList,
+		"screen -ls a":                  fakeList,
+		"screen -ls":                    fakeList,
+	}})
+
+	for _, name := range []string{"deploy europe-west", "a"} {
+		if s, err := m.Get(name); err != os.ErrNotExist {
+			t.Errorf("%q: got %+v, %v, want no screen", name, s, err)
+		}
+	}
+
+	screens, err := m.GetPrefix("deploy europe-west")
+	if err != nil || len(screens) != 2 || screens[0].Name != "deploy europe-west 1" || screens[1].Name != "deploy europe-west 10" {
+		t.Errorf("got %+v, %v", screens, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": fakeList}})
+
+	for pattern, want := range map[string]int{"deploy *": 2, "deploy * 1": 1, "a+b": 1, "*": 3, "[": 0, "build-*": 0} {
+		if got := m.Find(pattern); len(got) != want {
+			t.Errorf("%q: got %+v, want %d screens", pattern, got, want)
+		}
+	}
+	if got := m.FindRegexp(regexp.MustCompile(`^deploy .* \d+$`)); len(got) != 2 {
+		t.Errorf("got %+v", got)
+	}
+}
@@ -0,0 +1,532 @@
+package screen
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Manager manages the screens of a single host. The package-level functions (New, Get, GetAll) use a Manager that
+// runs everything on the local machine, other Managers run their commands somewhere else, i.e. inside a container.
+type Manager struct {
+	// DefaultShell is started by New when it's given no shell. If empty, the caller's $SHELL is used for local
+	// Managers, falling back to "/bin/sh".
+	DefaultShell string
+	// DefaultShellArgs are passed to DefaultShell (or whatever is used instead), i.e. []string{"--login"}.
+	DefaultShellArgs []string
+	// WindowTitle, if set, is the title New gives the first window of a screen (with "-t"), and the screen's
+	// "shelltitle", so windows opened later get it too. It makes the window-by-title lookups useful from the start.
+	WindowTitle string
+	// CompressLogs makes Screen.Log gzip a logfile once it's finished, meaning logging was switched off or moved to
+	// another file. The original file is removed.
+	CompressLogs bool
+	// OnLogFinished, if set, is called with the final path of every logfile Screen.Log finishes, ending in ".gz" if
+	// CompressLogs is set.
+	OnLogFinished func(s Screen, path string)
+	// OnEvent, if set, is called with everything that happens to the Manager's screens: screens made by New, and
+	// events of WatchSessions, WatchPatterns and Watchdogs. It's called synchronously, so it shouldn't block.
+	OnEvent func(e Event)
+	// ThroughputInterval is the interval Screen.Throughput counts output over, 10 seconds if zero.
+	ThroughputInterval time.Duration
+	// SocketDir is where screen keeps the current user's sockets on the Manager's host, if FindSocketDir gets it
+	// wrong. Screen itself follows $SCREENDIR.
+	SocketDir string
+	// Defaults are the timings the Manager waits and polls with, see Defaults.
+	Defaults Defaults
+	// Limits cap how many sessions New makes and how big logfiles get, see Limits. Over them, a *QuotaError is
+	// returned.
+	Limits Limits
+	// Metrics is told about every command run on the Manager's host, see Metrics. nil means NopMetrics.
+	Metrics Metrics
+	// Policy, if set, restricts which screen commands the Manager runs, and on which sessions. Others fail with a
+	// *PolicyError before they run, see Policy.
+	Policy *Policy
+	// Screenrc, if set, is used by New instead of the host user's ~/.screenrc, see Screenrc.
+	Screenrc *Screenrc
+	// Env, if not nil, is the whole environment of every screen command the Manager runs, and so of the screens it
+	// starts and their shells, rather than what they'd inherit, i.e. []string{"PATH=/usr/bin:/bin", "HOME=/root",
+	// "SCREENDIR=/run/ci-screens", "SCREENRC=/etc/ci.screenrc", "LC_ALL=C", "TERM=xterm"}. This keeps screens the
+	// same regardless of the host user's profile. Remember PATH, or shells won't find much.
+	Env []string
+	// Tags, if set, returns labels of a screen, i.e. from a naming scheme or an inventory, for Search to match.
+	Tags func(s Screen) []string
+	// OnCreate hooks run in order on every screen New makes, once it's up, i.e. CreateCommands("defscrollback 10000")
+	// so every screen starts out the same. If one fails, New quits the screen and returns the error.
+	OnCreate []CreateHook
+	// Preflight makes New check that the shell exists and is executable before starting a screen with it, rather than
+	// finding out from the screen dying (see ErrStartupFailed).
+	Preflight bool
+	// OnDuplicate is what New does when another screen with the same name shows up while it creates one.
+	OnDuplicate DuplicatePolicy
+	// Login sets whether windows of screens made by New register in utmp, so they show up in "who".
+	Login LoginMode
+	// TempDir is the directory on the Manager's host that its spool directory is made in, the host's temp directory
+	// by default. Hardcopies, logs, FIFOs and other files the Manager exchanges with screen live in the spool
+	// directory, which only the current user can read, and which Close removes. TempDir is created (with 0700) if
+	// it's missing, but never removed.
+	TempDir string
+
+	runner   Runner   // nil means ExecRunner{}
+	mutexes  sync.Map // Per-screen mutexes, keyed by name
+	logs     sync.Map // Current logfile of each screen, keyed by name
+	meters   sync.Map // Output counters of captured screens, keyed by name
+	captures sync.Map // Pipe of the current Capture of each screen, keyed by name
+	activity sync.Map // Time of the last command sent to each screen, keyed by "<PID>.<name>"
+	queries  queryCache
+
+	resources resources // Background work, stopped by Close
+
+	uidOnce  sync.Once
+	uidValue int // Cached by uid
+	uidErr   error
+
+	versionMutex sync.Mutex
+	version      *Version // Cached by Version
+
+	spoolOnce sync.Once
+	spool     string // Private directory on the host for our temporary files
+	spoolErr  error
+}
+
+// local is the Manager used by the package-level functions.
+var local = &Manager{}
+
+// NewManager returns a Manager for the local machine, which can be configured separately from the package-level
+// functions.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// NewManagerWithRunner returns a Manager that runs all of its commands through r.
+func NewManagerWithRunner(r Runner) *Manager {
+	return &Manager{runner: r}
+}
+
+// r returns the Manager's Runner.
+func (m *Manager) r() Runner {
+	if m.runner == nil {
+		return ExecRunner{}
+	}
+	return m.runner
+}
+
+// run runs a command on the Manager's host.
+func (m *Manager) run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error) {
+	return m.runEnv(ctx, nil, name, args...)
+}
+
+// runEnv runs a command like run, with extra environment variables ("KEY=value") on top of the Manager's Env.
+func (m *Manager) runEnv(ctx context.Context, extra []string, name string, args ...string) (stdout, stderr []byte, err error) {
+	if m.Defaults.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Defaults.CommandTimeout)
+		defer cancel()
+	}
+
+	if err = m.Policy.check(name, args); err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	envName, envArgs := m.withEnv(name, args, extra...)
+	stdout, stderr, err = m.r().Run(ctx, envName, envArgs...)
+	m.observe(time.Since(start), err, name, args...)
+	if err != nil {
+		err = &CommandError{Command: append([]string{name}, args...), Stdout: stdout, Stderr: stderr, Err: err}
+	}
+	m.commandSent(name, args)
+	return
+}
+
+// combined runs a command on the Manager's host, and returns its stdout and stderr together, like
+// exec.Cmd.CombinedOutput.
+func (m *Manager) combined(name string, args ...string) ([]byte, error) {
+	stdout, stderr, err := m.run(context.Background(), name, args...)
+	return append(stdout, stderr...), err
+}
+
+// command builds a command on the Manager's host for streaming its input or output. Canceling ctx kills it.
+func (m *Manager) command(ctx context.Context, name string, args ...string) (*exec.Cmd, error) {
+	c, ok := m.r().(Commander)
+	if !ok {
+		return nil, ErrNoCommander
+	}
+	if err := m.Policy.check(name, args); err != nil {
+		return nil, err
+	}
+	m.observe(0, nil, name, args...)
+	name, args = m.withEnv(name, args)
+	return c.Command(ctx, name, args...), nil
+}
+
+// ttyCommand builds a command like command, but asks the backend to pass a terminal through to it.
+func (m *Manager) ttyCommand(ctx context.Context, name string, args ...string) (*exec.Cmd, error) {
+	if r, ok := m.r().(ExecRunner); ok {
+		if err := m.Policy.check(name, args); err != nil {
+			return nil, err
+		}
+		m.observe(0, nil, name, args...)
+		name, args = m.withEnv(name, args)
+		return r.TTYCommand(ctx, name, args...), nil
+	}
+	return m.command(ctx, name, args...)
+}
+
+// defaultShell returns the shell New starts when it isn't given one.
+func (m *Manager) defaultShell() string {
+	if m.DefaultShell != "" {
+		return m.DefaultShell
+	}
+	if shell := os.Getenv("SHELL"); shell != "" && m.isLocal() {
+		return shell
+	}
+	return "/bin/sh"
+}
+
+// mutex loads the mutex for the given screen name, creating it if needed.
+func (m *Manager) mutex(name string) *sync.Mutex {
+	v, _ := m.mutexes.LoadOrStore(name, new(sync.Mutex))
+	mutex, _ := v.(*sync.Mutex)
+	return mutex
+}
+
+// isLocal reports whether the Manager's host is this machine, so files can be touched directly.
+func (m *Manager) isLocal() bool {
+	r, ok := m.r().(ExecRunner)
+	return ok && len(r.Prefix) == 0
+}
+
+// childPIDs lists the direct children of a process on the Manager's host.
+func (m *Manager) childPIDs(pid int) ([]int, error) {
+	out, err := m.combined("ps", "--no-headers", "--ppid", strconv.Itoa(pid), "-o", "pid:1")
+	if err != nil && len(out) > 0 { // ps exits 1 without output when there are none
+		return nil, err
+	}
+
+	var res []int
+	for _, field := range strings.Fields(string(out)) {
+		if child, err := strconv.Atoi(field); err == nil {
+			res = append(res, child)
+		}
+	}
+	return res, nil
+}
+
+// descendants lists every process below pid on the Manager's host, parents before their children.
+func (m *Manager) descendants(pid int) ([]int, error) {
+	children, err := m.childPIDs(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	res := children
+	for _, child := range children {
+		grandchildren, err := m.descendants(child)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, grandchildren...)
+	}
+	return res, nil
+}
+
+// signal sends a signal to processes of the screen target ("<PID>.<name>") on the Manager's host, if the Manager's
+// Policy allows it. Processes that are already gone are skipped.
+func (m *Manager) signal(target string, sig syscall.Signal, pids ...int) error {
+	if err := m.Policy.checkSignal(target); err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		_, err := m.combined("kill", "-"+strconv.Itoa(int(sig)), strconv.Itoa(pid))
+		if err != nil && m.stat("/proc/"+strconv.Itoa(pid)) == nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// procStartTime returns when a process started, in clock ticks since boot, from /proc/<pid>/stat on the Manager's
+// host. Together with the PID, it identifies a process even if its PID gets reused.
+func (m *Manager) procStartTime(pid int) (uint64, error) {
+	b, err := m.readFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name may contain anything, so count fields from its closing parenthesis. Start time is field 22.
+	i := bytes.LastIndexByte(b, ')')
+	fields := strings.Fields(string(b[i+1:]))
+	if i < 0 || len(fields) < 20 {
+		return 0, errors.New("unexpected format of /proc/" + strconv.Itoa(pid) + "/stat")
+	}
+	return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// finishLog compresses and reports a logfile Screen.Log is done with, as configured.
+func (m *Manager) finishLog(s Screen, path string) error {
+	if m.CompressLogs {
+		var err error
+		if path, err = m.gzip(path); err != nil {
+			return err
+		}
+	}
+	if m.OnLogFinished != nil {
+		m.OnLogFinished(s, path)
+	}
+	return nil
+}
+
+// =========================================================
+// ================== Host file helpers ====================
+// =========================================================
+
+// spoolDir returns the Manager's private directory on its host, creating it in TempDir on first use. Only the current
+// user can read it, so hardcopies and logs of sessions never sit in a world-readable place.
+func (m *Manager) spoolDir() (string, error) {
+	m.spoolOnce.Do(func() {
+		if m.isLocal() {
+			if m.TempDir != "" {
+				if m.spoolErr = os.MkdirAll(m.TempDir, 0700); m.spoolErr != nil {
+					return
+				}
+			}
+			m.spool, m.spoolErr = os.MkdirTemp(m.TempDir, "go-gnu-screen-*") // Created with 0700
+			return
+		}
+
+		args := []string{"-d", "-t", "go-gnu-screen-XXXXXXXX"}
+		if m.TempDir != "" {
+			if _, _, m.spoolErr = m.run(context.Background(), "mkdir", "-p", "-m", "700", m.TempDir); m.spoolErr != nil {
+				return
+			}
+			args = []string{"-d", "-p", m.TempDir, "go-gnu-screen-XXXXXXXX"}
+		}
+		out, _, err := m.run(context.Background(), "mktemp", args...)
+		if err != nil {
+			m.spoolErr = err
+			return
+		}
+		m.spool = strings.TrimSpace(string(out))
+	})
+
+	return m.spool, m.spoolErr
+}
+
+// tempFile creates an empty temporary file in the Manager's spool directory, and returns its path.
+func (m *Manager) tempFile() (string, error) {
+	dir, err := m.spoolDir()
+	if err != nil {
+		return "", err
+	}
+
+	if m.isLocal() {
+		f, err := os.CreateTemp(dir, "*")
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		return f.Name(), nil
+	}
+
+	out, _, err := m.run(context.Background(), "mktemp", "-p", dir)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fifo creates a named pipe in the Manager's spool directory, and returns its path.
+func (m *Manager) fifo() (string, error) {
+	dir, err := m.spoolDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Join(dir, strconv.FormatInt(time.Now().UnixNano(), 36)+".fifo")
+	if _, err := m.combined("mkfifo", "-m", "600", name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// readFile reads a file from the Manager's host.
+func (m *Manager) readFile(path string) ([]byte, error) {
+	if m.isLocal() {
+		return os.ReadFile(path)
+	}
+
+	out, _, err := m.run(context.Background(), "cat", path)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// writeFile writes a file on the Manager's host, only readable by the current user.
+func (m *Manager) writeFile(path string, data []byte) error {
+	if m.isLocal() {
+		return os.WriteFile(path, data, 0600)
+	}
+
+	cmd, err := m.command(context.Background(), "sh", "-c", `umask 077 && cat > "$1"`, "sh", path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return &CommandError{Command: cmd.Args, Stdout: out, Err: err}
+	}
+	return nil
+}
+
+// gzip compresses a file on the Manager's host into "<path>.gz", removes the original, and returns the new path.
+func (m *Manager) gzip(path string) (string, error) {
+	if !m.isLocal() {
+		if _, err := m.combined("gzip", "-f", path); err != nil {
+			return "", err
+		}
+		return path + ".gz", nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err != nil {
+		return "", err
+	}
+	if err = zw.Close(); err != nil {
+		return "", err
+	}
+	if err = out.Close(); err != nil {
+		return "", err
+	}
+
+	return path + ".gz", os.Remove(path)
+}
+
+// remove deletes a file from the Manager's host.
+func (m *Manager) remove(path string) error {
+	if m.isLocal() {
+		return os.Remove(path)
+	}
+
+	_, err := m.combined("rm", "-f", path)
+	return err
+}
+
+// listDir returns the names of the entries of a directory on the Manager's host.
+func (m *Manager) listDir(dir string) ([]string, error) {
+	if m.isLocal() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]string, 0, len(entries))
+		for _, e := range entries {
+			res = append(res, e.Name())
+		}
+		return res, nil
+	}
+
+	out, _, err := m.run(context.Background(), "ls", "-1A", dir)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// readlink returns the target of a symbolic link on the Manager's host.
+func (m *Manager) readlink(path string) (string, error) {
+	if m.isLocal() {
+		return os.Readlink(path)
+	}
+
+	out, _, err := m.run(context.Background(), "readlink", path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// stat checks that a path exists on the Manager's host. Missing paths return an ErrNotExist type.
+func (m *Manager) stat(path string) error {
+	if m.isLocal() {
+		_, err := os.Stat(path)
+		return err
+	}
+
+	if _, _, err := m.run(context.Background(), "test", "-e", path); err != nil {
+		return &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+// checkDir checks that a path is a directory on the Manager's host. Missing paths return an ErrNotExist type.
+func (m *Manager) checkDir(path string) error {
+	if m.isLocal() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New(path + " is not a directory")}
+		}
+		return nil
+	}
+
+	if err := m.stat(path); err != nil {
+		return err
+	}
+	if _, _, err := m.run(context.Background(), "test", "-d", path); err != nil {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New(path + " is not a directory")}
+	}
+	return nil
+}
+
+// fileMode returns the permission bits of a file on the Manager's host.
+func (m *Manager) fileMode(path string) (os.FileMode, error) {
+	if m.isLocal() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		return info.Mode().Perm(), nil
+	}
+
+	out, _, err := m.run(context.Background(), "stat", "-c", "%a", path)
+	if err != nil {
+		return 0, err
+	}
+	mode, err := strconv.ParseUint(strings.TrimSpace(string(out)), 8, 32)
+	return os.FileMode(mode), err
+}
+
+// truncate empties a file on the Manager's host.
+func (m *Manager) truncate(path string) error {
+	if m.isLocal() {
+		return os.Truncate(path, 0)
+	}
+
+	_, err := m.combined("truncate", "-s", "0", path)
+	return err
+}
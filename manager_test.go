@@ -0,0 +1,121 @@
+package screen
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestTempFileIsPrivate(t *testing.T) {
+	m := &Manager{}
+	name, err := m.tempFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(filepath.Dir(name))
+
+	info, err := os.Stat(filepath.Dir(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("spool directory has permissions %o, want 700", perm)
+	}
+
+	if err = m.remove(name); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTempDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spool")
+	m := &Manager{TempDir: dir}
+	spool, err := m.spoolDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(spool) != dir {
+		t.Errorf("spool directory %s isn't in %s", spool, dir)
+	}
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("TempDir wasn't created private: %v, %v", info, err)
+	}
+
+	r := &fakeRunner{outputs: map[string]string{
+		"mkdir -p -m 700 /srv/spool":                     "",
+		"mktemp -d -p /srv/spool go-gnu-screen-XXXXXXXX": "/srv/spool/go-gnu-screen-1a2b3c4d\n",
+	}}
+	m = NewManagerWithRunner(r)
+	m.TempDir = "/srv/spool"
+	if spool, err = m.spoolDir(); err != nil || spool != "/srv/spool/go-gnu-screen-1a2b3c4d" {
+		t.Errorf("got %q, %v on a remote host", spool, err)
+	}
+}
+
+func TestDefaultShell(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+
+	if got := NewManager().defaultShell(); got != "/bin/zsh" {
+		t.Errorf("local Manager got %q, want $SHELL", got)
+	}
+	if got := NewDockerManager("web").defaultShell(); got != "/bin/sh" {
+		t.Errorf("docker Manager got %q, want /bin/sh", got)
+	}
+	if got := (&Manager{DefaultShell: "bash"}).defaultShell(); got != "bash" {
+		t.Errorf("got %q, want DefaultShell", got)
+	}
+}
+
+func TestChildPIDs(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	children, err := NewManager().childPIDs(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pid := range children {
+		if pid == cmd.Process.Pid {
+			return
+		}
+	}
+	t.Errorf("child %d not in %v", cmd.Process.Pid, children)
+}
+
+func TestGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "screen.log")
+	if err := os.WriteFile(path, []byte("hello\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := NewManager().gzip(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gz != path+".gz" {
+		t.Errorf("got %q, want %q", gz, path+".gz")
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Error("original logfile wasn't removed")
+	}
+
+	f, err := os.Open(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); string(b) != "hello\n" {
+		t.Errorf("got %q", b)
+	}
+}
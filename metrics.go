@@ -0,0 +1,76 @@
+package screen
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// Metrics is told about every command a Manager runs on its host, so latency and failures can be exported to
+// Prometheus, StatsD or similar without this package picking a library. Streamed commands (Capture, Attach, ...) are
+// observed with a zero duration when they're built, since they may run for as long as the caller likes.
+type Metrics interface {
+	// ObserveCommand is called after a command finishes. name is the screen command or query (i.e. "stuff", "windows"), or
+	// "ls", "new", "attach" and "version" for screen's own flags, or the program for anything else (i.e. "kill").
+	// session is the "-S" target, empty if there is none.
+	ObserveCommand(name, session string, duration time.Duration, err error)
+}
+
+// NopMetrics is a Metrics that does nothing, the default of Managers.
+type NopMetrics struct{}
+
+// ObserveCommand does nothing.
+func (NopMetrics) ObserveCommand(string, string, time.Duration, error) {}
+
+// metrics returns the Manager's Metrics.
+func (m *Manager) metrics() Metrics {
+	if m.Metrics == nil {
+		return NopMetrics{}
+	}
+	return m.Metrics
+}
+
+// observe reports a command to the Manager's Metrics.
+func (m *Manager) observe(d time.Duration, err error, name string, args ...string) {
+	cmd, session := describeCommand(name, args)
+	m.metrics().ObserveCommand(cmd, session, d, err)
+}
+
+// describeCommand returns the name and session of a command, for Metrics.
+func describeCommand(name string, args []string) (cmd, session string) {
+	cmd = path.Base(name)
+	if cmd != "screen" {
+		return cmd, ""
+	}
+
+	for i, arg := range args {
+		switch arg {
+		case "-S":
+			if i+1 < len(args) {
+				session = args[i+1]
+			}
+		case "-dmS":
+			if i+1 < len(args) {
+				session = args[i+1]
+			}
+			return "new", session // The rest is flags and the shell's arguments
+		case "-X", "-Q":
+			if i+1 < len(args) {
+				return args[i+1], session
+			}
+		case "-ls", "-list":
+			cmd = "ls"
+			if i+1 < len(args) && args[i+1] != "-q" {
+				session = args[i+1]
+			}
+		case "-v":
+			cmd = "version"
+		case "-r", "-x", "-R":
+			cmd = "attach"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				session = args[i+1]
+			}
+		}
+	}
+	return cmd, session
+}
@@ -0,0 +1,57 @@
+package screen
+
+import (
+	"testing"
+	"time"
+)
+
+type observation struct {
+	name, session string
+	failed        bool
+}
+
+type recordingMetrics []observation
+
+func (r *recordingMetrics) ObserveCommand(name, session string, duration time.Duration, err error) {
+	*r = append(*r, observation{name, session, err != nil})
+}
+
+func TestMetrics(t *testing.T) {
+	var rec recordingMetrics
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls a+b": fakeList}})
+	m.Metrics = &rec
+
+	if _, err := m.Get("a+b"); err != nil {
+		t.Fatal(err)
+	}
+	m.combined("kill", "-0", "1")
+
+	// Get also reads the process' start time, which isn't checked here
+	if len(rec) < 2 || rec[0] != (observation{"ls", "a+b", false}) || rec[len(rec)-1] != (observation{"kill", "", true}) {
+		t.Errorf("got %+v", rec)
+	}
+}
+
+func TestDescribeCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		cmd, session string
+	}{
+		{"/usr/bin/screen", []string{"-S", "42.build", "-X", "stuff", "ls\n"}, "stuff", "42.build"},
+		{"/usr/bin/screen", []string{"-dmS", "build", "-l", "/bin/sh"}, "new", "build"},
+		{"/usr/bin/screen", []string{"-dmS", "build", "/bin/bash", "-x"}, "new", "build"},
+		{"/usr/bin/screen", []string{"-D", "-R", "42.build"}, "attach", "42.build"},
+		{"/usr/bin/screen", []string{"-S", "42.build", "-p", "0", "-Q", "title"}, "title", "42.build"},
+		{"screen", []string{"-ls", "-q"}, "ls", ""},
+		{"screen", []string{"-ls"}, "ls", ""},
+		{"/usr/bin/screen", []string{"-v"}, "version", ""},
+		{"/bin/kill", []string{"-15", "42"}, "kill", ""},
+	}
+
+	for _, tt := range tests {
+		if cmd, session := describeCommand(tt.name, tt.args); cmd != tt.cmd || session != tt.session {
+			t.Errorf("%s %v: got %q, %q, want %q, %q", tt.name, tt.args, cmd, session, tt.cmd, tt.session)
+		}
+	}
+}
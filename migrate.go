@@ -0,0 +1,113 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+)
+
+// SessionDefinition is what it takes to recreate a screen somewhere else, see Export and Migrate.
+type SessionDefinition struct {
+	Name       string
+	Shell      []string // Command line of the process in window 0
+	Dir        string   // Working directory of that process
+	Scrollback string   // Scrollback and display of the screen's current window
+}
+
+// MigrateOptions changes what Migrate does.
+type MigrateOptions struct {
+	KillSource bool // Quit the original screen once the new one is running
+}
+
+// Export collects the screen's definition: its name, what runs in its first window and where, and what the screen
+// shows, including its scrollback.
+func (s Screen) Export() (def SessionDefinition, err error) {
+	def.Name = s.Name
+
+	pid, err := s.ShellPID()
+	if err != nil {
+		return
+	}
+	cmdline, err := s.m().readFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+	if err != nil {
+		return
+	}
+	def.Shell = parseCmdline(cmdline)
+	if def.Dir, err = s.m().readlink("/proc/" + strconv.Itoa(pid) + "/cwd"); err != nil {
+		return
+	}
+
+	name, err := s.m().tempFile()
+	if err != nil {
+		return
+	}
+	defer s.m().remove(name)
+
+	if err = s.hardcopy(name, false, true); err != nil {
+		return
+	}
+	b, err := s.m().readFile(name)
+	def.Scrollback = string(b)
+	return
+}
+
+// parseCmdline splits the contents of /proc/<pid>/cmdline into arguments. Login shells are started with a "-" in
+// front of their name ("-bash"), which is dropped so the command can be run again.
+func parseCmdline(cmdline []byte) (args []string) {
+	for _, arg := range bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0}) {
+		args = append(args, string(arg))
+	}
+	if len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		args[0] = args[0][1:]
+	}
+	return
+}
+
+// Migrate moves a screen to the target Manager's host: it exports the screen's definition, and starts the same
+// command in the same directory under the same name on the target, where the old scrollback is printed before the
+// command starts. Only what's on the screen moves, the processes themselves can't, so the command starts over.
+func Migrate(ctx context.Context, s Screen, target *Manager, opts MigrateOptions) (Screen, error) {
+	def, err := s.Export()
+	if err != nil {
+		return Screen{}, err
+	}
+
+	res, err := target.Import(ctx, def)
+	if err != nil {
+		return Screen{}, err
+	}
+
+	if opts.KillSource {
+		if err = s.Quit(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// importWrapper is run by "sh -c" with the scrollback file, the directory and the command as arguments. It prints the
+// old scrollback, removes it, and replaces itself with the command.
+const importWrapper = `cat "$0"; rm -f "$0"; [ -z "$1" ] || cd "$1"; shift; exec "$@"`
+
+// Import creates a screen from a definition made by Export.
+func (m *Manager) Import(ctx context.Context, def SessionDefinition) (Screen, error) {
+	if len(def.Shell) == 0 {
+		def.Shell = []string{m.defaultShell()}
+	}
+
+	scrollback, err := m.tempFile()
+	if err != nil {
+		return Screen{}, err
+	}
+	if err = m.writeFile(scrollback, []byte(def.Scrollback)); err != nil {
+		m.remove(scrollback)
+		return Screen{}, err
+	}
+
+	shell := append([]string{"/bin/sh", "-c", importWrapper, scrollback, def.Dir}, def.Shell...)
+	s, err := m.New(ctx, def.Name, shell...)
+	if err != nil {
+		m.remove(scrollback)
+	}
+	return s, err
+}
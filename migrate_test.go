@@ -0,0 +1,56 @@
+package screen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestImportWrapper(t *testing.T) {
+	dir := t.TempDir()
+	scrollback := filepath.Join(dir, "scrollback")
+	if err := os.WriteFile(scrollback, []byte("$ make\nok\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("/bin/sh", "-c", importWrapper, scrollback, dir, "pwd").CombinedOutput()
+	if err != nil {
+		t.Fatal(string(out), err)
+	}
+	if want := "$ make\nok\n" + dir + "\n"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+	if _, err = os.Stat(scrollback); !os.IsNotExist(err) {
+		t.Error("scrollback file wasn't removed")
+	}
+}
+
+func TestParseCmdline(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    []string
+	}{
+		{"-bash\x00", []string{"bash"}},
+		{"-sh\x00", []string{"sh"}},
+		{"/usr/bin/python3\x00-u\x00app.py\x00", []string{"/usr/bin/python3", "-u", "app.py"}},
+		{"-\x00", []string{"-"}},
+	}
+	for _, test := range tests {
+		if got := parseCmdline([]byte(test.cmdline)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.cmdline, got, test.want)
+		}
+	}
+
+	// A login shell's command line runs again through the wrapper
+	scrollback := filepath.Join(t.TempDir(), "scrollback")
+	if err := os.WriteFile(scrollback, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	args := append([]string{"-c", importWrapper, scrollback, ""}, parseCmdline([]byte("-sh\x00"))...)
+	out, err := exec.Command("/bin/sh", append(args, "-c", "echo ok")...).CombinedOutput()
+	if err != nil || string(out) != "ok\n" {
+		t.Errorf("got %q, %v", out, err)
+	}
+}
@@ -0,0 +1,52 @@
+package screen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxNameLength is how long a name may be. Screen puts "<PID>.<name>" into a socket path, which is limited to 108
+// bytes including the socket directory, and it rejects names longer than 80 bytes outright.
+const maxNameLength = 80
+
+// ValidateName checks that screen accepts name as a session name.
+func ValidateName(name string) error {
+	invalid := func(reason string) error {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid screen name " + name + ": " + reason)}
+	}
+
+	switch {
+	case name == "":
+		return invalid("empty")
+	case len(name) > maxNameLength:
+		return invalid("too long")
+	case strings.Contains(name, "/"):
+		return invalid("contains a slash")
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return invalid("contains a control character")
+	}
+	return nil
+}
+
+// GenerateName returns a session name that won't collide with others: prefix, the current time, and a random suffix,
+// i.e. "build-20261015-093000-9f86d081".
+func GenerateName(prefix string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	name := time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+	if prefix != "" {
+		name = prefix + "-" + name
+	}
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,39 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := map[string]bool{
+		"banana":                true,
+		"deploy europe-west 1":  true,
+		"日本語":                   true,
+		"":                      false,
+		"a/b":                   false,
+		"tab\there":             false,
+		strings.Repeat("x", 81): false,
+	}
+
+	for name, valid := range tests {
+		if err := ValidateName(name); (err == nil) != valid {
+			t.Errorf("ValidateName(%q) = %v, want valid %v", name, err, valid)
+		}
+	}
+}
+
+func TestGenerateName(t *testing.T) {
+	a, err := GenerateName("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateName("build")
+
+	if !strings.HasPrefix(a, "build-") || a == b {
+		t.Errorf("got %q and %q", a, b)
+	}
+	if _, err = GenerateName("a/b"); err == nil {
+		t.Error("expected an error for an invalid prefix")
+	}
+}
@@ -0,0 +1,116 @@
+package screen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// InnerScreen is a screen running in a window of another screen, i.e. one attached to on a jump host. Stuffing into
+// the outer window reaches the inner screen's window, and its commands are typed behind its command key, which the
+// outer screen passes through like any other input.
+type InnerScreen struct {
+	Window Window // Of the outer screen, where the inner one runs
+	Escape byte   // The inner screen's command key, Ctrl-A unless it was started with another
+	PID    int    // Of the inner screen's client, 0 if it was declared with Nested
+}
+
+// Inner finds a screen running in the window, i.e. "screen -r" started from its shell. If there is none,
+// ErrNotExist type is returned. Only screens on the same host show up; for one behind ssh, use Nested.
+func (w Window) Inner() (InnerScreen, error) {
+	pid, err := w.PID()
+	if err != nil {
+		return InnerScreen{}, err
+	}
+	pids, err := w.Screen.m().descendants(pid)
+	if err != nil {
+		return InnerScreen{}, err
+	}
+
+	for _, p := range append([]int{pid}, pids...) {
+		cmdline, err := w.Screen.m().readFile("/proc/" + strconv.Itoa(p) + "/cmdline")
+		if err != nil {
+			continue // Gone already
+		}
+		args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+		if path.Base(args[0]) != "screen" {
+			continue
+		}
+
+		in := InnerScreen{Window: w, Escape: 0x01, PID: p}
+		for i, arg := range args[1:] {
+			escape := ""
+			if arg == "-e" && i+2 < len(args) {
+				escape = args[i+2]
+			} else if strings.HasPrefix(arg, "-e") {
+				escape = arg[2:]
+			}
+			if key, err := parseEscape(escape); escape != "" && err == nil {
+				in.Escape = key
+			}
+		}
+		return in, nil
+	}
+	return InnerScreen{}, &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("no screen running in window " + strconv.Itoa(w.Number))}
+}
+
+// Nested declares that the window runs another screen, whose command key is escape in screen's notation (i.e. "^Bb",
+// see "escape" in "man screen"). Empty means screen's default, Ctrl-A.
+func (w Window) Nested(escape string) (InnerScreen, error) {
+	in := InnerScreen{Window: w, Escape: 0x01}
+	if escape == "" {
+		return in, nil
+	}
+	key, err := parseEscape(escape)
+	if err != nil {
+		return InnerScreen{}, err
+	}
+	in.Escape = key
+	return in, nil
+}
+
+// parseEscape returns the command key of an "escape" setting, its first character: "^a" is Ctrl-A, "\\" a backslash,
+// "\002" an octal code, anything else itself.
+func parseEscape(escape string) (byte, error) {
+	invalid := &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid escape " + strconv.Quote(escape))}
+	switch {
+	case escape == "":
+		return 0, invalid
+	case escape[0] == '^' && len(escape) > 1:
+		if escape[1] == '?' {
+			return 0x7f, nil
+		}
+		return escape[1] & 0x1f, nil
+	case escape[0] == '\\' && len(escape) > 3 && escape[1] >= '0' && escape[1] <= '3':
+		code, err := strconv.ParseUint(escape[1:4], 8, 8)
+		if err != nil {
+			return 0, invalid
+		}
+		return byte(code), nil
+	case escape[0] == '\\' && len(escape) > 1:
+		return escape[1], nil
+	default:
+		return escape[0], nil
+	}
+}
+
+// Stuff types text into the inner screen's current window.
+func (in InnerScreen) Stuff(text string) error {
+	return in.Window.builtin("stuff", text)
+}
+
+// Command runs a screen command in the inner screen, like "screen -X" would, by typing it at its command prompt.
+// Arguments are quoted as needed.
+func (in InnerScreen) Command(command string, args ...string) error {
+	var b bytes.Buffer
+	b.WriteByte(in.Escape)
+	b.WriteString(":" + command)
+	for _, arg := range args {
+		b.WriteString(" " + quoteArg(arg))
+	}
+	b.WriteByte('\r')
+	return in.Stuff(b.String())
+}
@@ -0,0 +1,49 @@
+package screen
+
+import (
+	"testing"
+)
+
+func TestParseEscape(t *testing.T) {
+	for in, want := range map[string]byte{"^a": 0x01, "^Bb": 0x02, "^?": 0x7f, "\\002a": 0x02, "\\\\": '\\', "``": '`'} {
+		if got, err := parseEscape(in); err != nil || got != want {
+			t.Errorf("%q: got %#x, %v, want %#x", in, got, err, want)
+		}
+	}
+	if _, err := parseEscape(""); err == nil {
+		t.Error("empty escape accepted")
+	}
+}
+
+func TestWindowInner(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                            fakeList,
+		"ps --no-headers --ppid 4242 -o pid:1":      "4300\n",
+		"ps --no-headers --ppid 4300 -o pid:1":      "4301\n",
+		"ps --no-headers --ppid 4301 -o pid:1":      "",
+		"cat /proc/4300/environ":                    "WINDOW=0\x00",
+		"cat /proc/4300/stat":                       fakeStat("4300", "500"),
+		"cat /proc/4300/cmdline":                    "-bash\x00",
+		"cat /proc/4301/cmdline":                    "/usr/bin/screen\x00-e\x00^Bb\x00-r\x00",
+		screen + "-p 0 -X stuff \x02:title 'a b'\r": "",
+	}}
+	m := NewManagerWithRunner(r)
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := s.Window(0).Inner()
+	if err != nil || in.PID != 4301 || in.Escape != 0x02 {
+		t.Fatalf("got %+v, %v", in, err)
+	}
+	if err = in.Command("title", "a b"); err != nil {
+		t.Error(err)
+	}
+
+	r.outputs["cat /proc/4301/cmdline"] = "ssh\x00jump\x00"
+	if _, err = s.Window(0).Inner(); err == nil {
+		t.Error("found a screen behind ssh")
+	}
+}
@@ -0,0 +1,155 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotOwner is returned when a screen's socket, or the directory it's in, belongs to another user.
+	ErrNotOwner = errors.New("socket belongs to another user")
+	// ErrBadSocketPerms is returned when a screen's socket, or the directory it's in, can be accessed by other users.
+	ErrBadSocketPerms = errors.New("socket has unsafe permissions")
+)
+
+// fileOwner is who owns a file, and its permission bits.
+type fileOwner struct {
+	uid  int // -1 if unknown
+	mode os.FileMode
+}
+
+// verify makes sure the screen is still running, and that its socket belongs to us with sane permissions, before
+// commanding it. Anything that can't be checked is left for screen to complain about.
+func (s Screen) verify() error {
+	status, err := s.Status()
+	if IsNotFound(err) {
+		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	} else if err != nil {
+		return err
+	}
+	if status.SocketPath == "" {
+		return nil
+	}
+	return s.m().checkSocket(status.SocketPath)
+}
+
+// checkSocket checks the owner and permissions of a socket, and the directory it's in.
+func (m *Manager) checkSocket(socket string) error {
+	uid, err := m.uid()
+	if err != nil || uid < 0 {
+		return nil
+	}
+	dir := path.Dir(socket)
+	owners, err := m.owners(dir, socket)
+	if err != nil {
+		return nil
+	}
+
+	for i, p := range []string{dir, socket} {
+		switch o := owners[i]; {
+		case o.uid >= 0 && o.uid != uid:
+			return fmt.Errorf("%w: %s belongs to uid %d, not %d", ErrNotOwner, p, o.uid, uid)
+		case o.mode&0066 != 0: // The execute bits of sockets tell whether they're attached, or multiuser
+			return fmt.Errorf("%w: %s has mode %#o", ErrBadSocketPerms, p, o.mode)
+		}
+	}
+	return nil
+}
+
+// CheckSocketDir checks the socket directory of the local machine, see Manager.CheckSocketDir.
+func CheckSocketDir() (fix string, err error) {
+	return local.CheckSocketDir()
+}
+
+// CheckSocketDir checks the directory screen keeps the current user's sockets in on the Manager's host, the way
+// screen does. If screen would refuse to use it, it returns ErrNotOwner, ErrBadSocketPerms or ErrSocketDirPermission,
+// along with shell commands fixing it (which may need root). A directory that doesn't exist yet is fine, as long as
+// screen can create it.
+func (m *Manager) CheckSocketDir() (fix string, err error) {
+	dir, err := m.FindSocketDir()
+	if err != nil {
+		// Not created yet, so check where screen would create it
+		candidates, _, candErr := m.socketDirCandidates()
+		if candErr != nil || len(candidates) == 0 {
+			return "", err
+		}
+		dir = candidates[0]
+	}
+	uid, err := m.uid()
+	if err != nil {
+		return "", err
+	}
+
+	owners, err := m.owners(dir)
+	if err != nil {
+		parent := path.Dir(dir)
+		if _, err = m.owners(parent); err != nil {
+			return "sudo mkdir -p -m 1777 " + parent, fmt.Errorf("%w: %s doesn't exist", ErrSocketDirPermission, parent)
+		}
+		return "", nil
+	}
+
+	switch o := owners[0]; {
+	case o.uid >= 0 && o.uid != uid:
+		return "sudo chown " + strconv.Itoa(uid) + " " + dir + " && chmod 700 " + dir,
+			fmt.Errorf("%w: %s belongs to uid %d, not %d", ErrNotOwner, dir, o.uid, uid)
+	case o.mode&0077 != 0:
+		return "chmod 700 " + dir, fmt.Errorf("%w: %s has mode %#o, screen wants 0700", ErrBadSocketPerms, dir, o.mode)
+	}
+	return "", nil
+}
+
+// uid returns the ID of the user the Manager's commands run as, -1 if it can't tell.
+func (m *Manager) uid() (int, error) {
+	m.uidOnce.Do(func() {
+		if m.isLocal() {
+			m.uidValue = os.Getuid()
+			return
+		}
+
+		out, _, err := m.run(context.Background(), "id", "-u")
+		if err != nil {
+			m.uidErr = err
+			return
+		}
+		m.uidValue, m.uidErr = strconv.Atoi(strings.TrimSpace(string(out)))
+	})
+	return m.uidValue, m.uidErr
+}
+
+// owners returns the owners and permission bits of files on the Manager's host.
+func (m *Manager) owners(paths ...string) ([]fileOwner, error) {
+	res := make([]fileOwner, 0, len(paths))
+	if m.isLocal() {
+		for _, p := range paths {
+			info, err := os.Stat(p)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, fileOwner{uid: fileUID(info), mode: info.Mode().Perm()})
+		}
+		return res, nil
+	}
+
+	out, _, err := m.run(context.Background(), "stat", append([]string{"-c", "%u %a"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		var uid int
+		var mode uint32
+		if _, err = fmt.Sscanf(line, "%d %o", &uid, &mode); err != nil {
+			return nil, err
+		}
+		res = append(res, fileOwner{uid: uid, mode: os.FileMode(mode).Perm()})
+	}
+	if len(res) != len(paths) {
+		return nil, errors.New("unexpected output of stat: " + string(out))
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package screen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifySocket(t *testing.T) {
+	const stat = "stat -c %u %a /run/screen/S-root /run/screen/S-root/4242.a+b"
+	tests := []struct {
+		stat string
+		want error
+	}{
+		{"1000 700\n1000 700\n", nil},
+		{"1000 700\n1000 600\n", nil},
+		{"1000 700\n1001 600\n", ErrNotOwner},
+		{"0 700\n1000 600\n", ErrNotOwner},
+		{"1000 755\n1000 600\n", ErrBadSocketPerms},
+		{"1000 700\n1000 666\n", ErrBadSocketPerms},
+	}
+
+	for _, tt := range tests {
+		r := &fakeRunner{outputs: map[string]string{
+			"id -u":          "1000\n",
+			"screen -ls a+b": fakeList,
+			stat:             tt.stat,
+			"/usr/bin/screen -S 4242.a+b -X stuff hi": "",
+		}}
+		m := NewManagerWithRunner(r)
+		s, err := m.Get("a+b")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = s.Stuff("hi"); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%q: got %v, want %v", tt.stat, err, tt.want)
+		}
+	}
+}
+
+func TestVerifyNotFound(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{"screen -ls a+b": fakeList}}
+	m := NewManagerWithRunner(r)
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A screen that can't be listed isn't a screen that's gone
+	m.Policy = &Policy{Deny: []string{"ls"}}
+	var policyErr *PolicyError
+	if err = s.verify(); !errors.As(err, &policyErr) || IsNotFound(err) {
+		t.Errorf("denied: got %v", err)
+	}
+	m.Policy = nil
+	r.outputs["screen -ls a+b"] = "bash: screen: command not found\n"
+	r.exits = map[string]int{"screen -ls a+b": 127}
+	if err = s.verify(); !errors.Is(err, ErrNotInstalled) || IsNotFound(err) {
+		t.Errorf("not installed: got %v", err)
+	}
+
+	r.outputs["screen -ls a+b"] = "No Sockets found in /run/screen/S-root.\n"
+	r.exits = map[string]int{"screen -ls a+b": 1}
+	if err = s.verify(); !IsNotFound(err) {
+		t.Errorf("gone: got %v", err)
+	}
+}
+
+func TestCheckSocketDir(t *testing.T) {
+	tests := []struct {
+		stat, fix string
+		want      error
+	}{
+		{"1000 700\n", "", nil},
+		{"1000 755\n", "chmod 700 /run/screen/S-root", ErrBadSocketPerms},
+		{"0 700\n", "sudo chown 1000 /run/screen/S-root && chmod 700 /run/screen/S-root", ErrNotOwner},
+	}
+
+	for _, tt := range tests {
+		m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+			"id -u":                            "1000\n",
+			"screen -ls":                       fakeList,
+			"stat -c %u %a /run/screen/S-root": tt.stat,
+		}})
+		fix, err := m.CheckSocketDir()
+		if fix != tt.fix || !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%q: got %q, %v, want %q, %v", tt.stat, fix, err, tt.fix, tt.want)
+		}
+	}
+
+	// A missing directory is created by screen, unless its parent is missing too
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"id -u": "1000\n", "screen -ls": fakeList}})
+	if fix, err := m.CheckSocketDir(); fix != "sudo mkdir -p -m 1777 /run/screen" || !errors.Is(err, ErrSocketDirPermission) {
+		t.Errorf("got %q, %v", fix, err)
+	}
+}
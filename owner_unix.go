@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package screen
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileUID returns the user owning a file, -1 if it can't tell.
+func fileUID(info os.FileInfo) int {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid)
+	}
+	return -1
+}
@@ -0,0 +1,121 @@
+package screen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Transcript is a recorded session, as written by a Recorder or by asciinema.
+type Transcript []TranscriptEvent
+
+// ReadTranscript reads a transcript written by a Recorder, or an asciicast (version 2, as written by "asciinema rec").
+func ReadTranscript(r io.Reader) (Transcript, error) {
+	var res Transcript
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var start time.Time // Of an asciicast, whose events are relative to it
+	asciicast := false
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var err error
+		switch {
+		case line == 1 && b[0] == '{' && bytes.Contains(b, []byte(`"version"`)):
+			var header struct {
+				Version   int   `json:"version"`
+				Timestamp int64 `json:"timestamp"`
+			}
+			if err = json.Unmarshal(b, &header); err == nil && header.Version != 2 {
+				err = fmt.Errorf("unsupported asciicast version %d", header.Version)
+			}
+			asciicast, start = true, time.Unix(header.Timestamp, 0)
+		case asciicast:
+			var e TranscriptEvent
+			if e, err = asciicastEvent(b, start); err == nil && e.Kind != "" {
+				res = append(res, e)
+			}
+		default:
+			var e TranscriptEvent
+			if err = json.Unmarshal(b, &e); err == nil {
+				res = append(res, e)
+			}
+		}
+		if err != nil {
+			return nil, &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: fmt.Errorf("transcript line %d: %w", line, err)}
+		}
+	}
+
+	return res, sc.Err()
+}
+
+// asciicastEvent converts an asciicast event, [seconds, code, data], into a TranscriptEvent. Events other than input
+// and output (i.e. resizes) are returned without a Kind.
+func asciicastEvent(b []byte, start time.Time) (e TranscriptEvent, err error) {
+	var fields []json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return
+	}
+	if len(fields) != 3 {
+		return e, errors.New("asciicast events have 3 fields")
+	}
+
+	var seconds float64
+	var code string
+	if err = json.Unmarshal(fields[0], &seconds); err != nil {
+		return
+	}
+	if err = json.Unmarshal(fields[1], &code); err != nil {
+		return
+	}
+	if err = json.Unmarshal(fields[2], &e.Data); err != nil {
+		return
+	}
+
+	e.Time = start.Add(time.Duration(seconds * float64(time.Second)))
+	switch code {
+	case "o":
+		e.Kind = TranscriptOutput
+	case "i":
+		e.Kind = TranscriptInput
+	}
+	return
+}
+
+// Playback writes the transcript's output to w with its original timing, i.e. to a terminal, so the session can be
+// watched again. speed scales the timing: 2 plays twice as fast, 0 or less writes everything at once. Input isn't
+// written, since the screen echoes whatever was typed anyway.
+func (t Transcript) Playback(ctx context.Context, w io.Writer, speed float64) error {
+	var last time.Time
+	for _, e := range t {
+		if e.Kind != TranscriptOutput {
+			continue
+		}
+
+		if !last.IsZero() && speed > 0 {
+			if wait := time.Duration(float64(e.Time.Sub(last)) / speed); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		last = e.Time
+
+		if _, err := io.WriteString(w, e.Data); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,44 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTranscript(t *testing.T) {
+	recorded := `{"time":"2026-10-15T10:00:00Z","kind":"input","data":"ls\n"}
+{"time":"2026-10-15T10:00:00.1Z","kind":"output","data":"ls\r\n"}
+{"time":"2026-10-15T10:00:00.2Z","kind":"output","data":"a b\r\n"}
+`
+	asciicast := `{"version": 2, "width": 80, "height": 24, "timestamp": 1791972000}
+[0.1, "o", "ls\r\n"]
+[0.15, "r", "100x30"]
+[0.2, "o", "a b\r\n"]
+`
+
+	for name, text := range map[string]string{"recorder": recorded, "asciicast": asciicast} {
+		tr, err := ReadTranscript(strings.NewReader(text))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		var out bytes.Buffer
+		start := time.Now()
+		if err = tr.Playback(context.Background(), &out, 2); err != nil {
+			t.Fatal(name, err)
+		}
+		if out.String() != "ls\r\na b\r\n" {
+			t.Errorf("%s: played back %q", name, out.String())
+		}
+		if elapsed := time.Since(start); elapsed < time.Millisecond*50 {
+			t.Errorf("%s: took %v, want at least 50ms at double speed", name, elapsed)
+		}
+	}
+
+	if _, err := ReadTranscript(strings.NewReader("{\"version\": 1}\n")); err == nil {
+		t.Error("accepted an asciicast v1")
+	}
+}
@@ -0,0 +1,226 @@
+package screen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Playbook describes screens to create and what to do in them, so sessions can be scripted without writing Go, i.e.
+// with "goscreen run". It's read from JSON by ReadPlaybook:
+//
+//	{
+//	  "profiles": {"build": {"shell": ["/bin/bash"], "dir": "/srv/app", "env": ["CI=1"]}},
+//	  "sessions": [{
+//	    "name": "deploy", "create": true, "profile": "build",
+//	    "steps": [{"cmd": "git pull"}, {"expect": "Already up to date", "timeout": "1m"}, {"hardcopy": true}]
+//	  }]
+//	}
+type Playbook struct {
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	Sessions []PlaybookSession  `json:"sessions"`
+}
+
+// Profile is a reusable set of settings for creating screens in a Playbook.
+type Profile struct {
+	Shell []string `json:"shell,omitempty"` // The Manager's DefaultShell if empty
+	Dir   string   `json:"dir,omitempty"`
+	Env   []string `json:"env,omitempty"` // "KEY=value"
+}
+
+// PlaybookSession is a screen of a Playbook, and the steps to run in it.
+type PlaybookSession struct {
+	Name    string         `json:"name"`
+	Create  bool           `json:"create,omitempty"`  // Create the screen, instead of using an existing one
+	Profile string         `json:"profile,omitempty"` // Name of the Profile to create it with
+	Steps   []PlaybookStep `json:"steps"`
+}
+
+// PlaybookStep is one thing to do in a screen. Exactly one of its actions must be set.
+type PlaybookStep struct {
+	Cmd      string `json:"cmd,omitempty"`      // Type a line, followed by a newline
+	Stuff    string `json:"stuff,omitempty"`    // Type text as it is
+	Expect   string `json:"expect,omitempty"`   // Wait for output matching a regular expression
+	Prompt   bool   `json:"prompt,omitempty"`   // Wait for a shell prompt, see Driver.WaitPrompt
+	Sleep    string `json:"sleep,omitempty"`    // Wait for a duration, i.e. "500ms"
+	Hardcopy bool   `json:"hardcopy,omitempty"` // Collect what the screen shows into the step's result
+	Quit     bool   `json:"quit,omitempty"`     // Quit the screen
+
+	Timeout string `json:"timeout,omitempty"` // For expect and prompt, 30 seconds if empty
+}
+
+// PlaybookResult is what running a Playbook did.
+type PlaybookResult struct {
+	OK       bool                    `json:"ok"`
+	Sessions []PlaybookSessionResult `json:"sessions"`
+}
+
+// PlaybookSessionResult is what running a PlaybookSession did. Steps after a failing one aren't run.
+type PlaybookSessionResult struct {
+	Name  string               `json:"name"`
+	OK    bool                 `json:"ok"`
+	Error string               `json:"error,omitempty"` // Why the session couldn't be created or found
+	Steps []PlaybookStepResult `json:"steps"`
+}
+
+// PlaybookStepResult is what running a PlaybookStep did.
+type PlaybookStepResult struct {
+	Step     PlaybookStep  `json:"step"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+	Output   string        `json:"output,omitempty"` // What expect or prompt waited through, or the hardcopy
+	Duration time.Duration `json:"duration"`
+}
+
+// ReadPlaybook reads a Playbook from JSON, and checks it for mistakes.
+func ReadPlaybook(r io.Reader) (pb Playbook, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields() // Catch typos, which would otherwise silently skip a step
+	if err = dec.Decode(&pb); err != nil {
+		return
+	}
+	return pb, pb.Validate()
+}
+
+// Validate checks that every session and step of the playbook makes sense.
+func (pb Playbook) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: fmt.Errorf("playbook: "+format, args...)}
+	}
+
+	for i, session := range pb.Sessions {
+		if err := ValidateName(session.Name); err != nil {
+			return invalid("session %d: %v", i, err)
+		}
+		if _, ok := pb.Profiles[session.Profile]; session.Profile != "" && !ok {
+			return invalid("session %s: no profile %s", session.Name, session.Profile)
+		}
+
+		for j, step := range session.Steps {
+			actions := 0
+			for _, set := range []bool{step.Cmd != "", step.Stuff != "", step.Expect != "", step.Prompt, step.Sleep != "",
+				step.Hardcopy, step.Quit} {
+				if set {
+					actions++
+				}
+			}
+			if actions != 1 {
+				return invalid("session %s, step %d: needs exactly one action, has %d", session.Name, j, actions)
+			}
+
+			for _, d := range []string{step.Sleep, step.Timeout} {
+				if _, err := time.ParseDuration(d); d != "" && err != nil {
+					return invalid("session %s, step %d: %v", session.Name, j, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RunPlaybook runs a Playbook on the Manager's host, one session after another. Canceling ctx fails the step that's
+// running, and everything after it.
+func (m *Manager) RunPlaybook(ctx context.Context, pb Playbook) PlaybookResult {
+	res := PlaybookResult{OK: true}
+	for _, session := range pb.Sessions {
+		r := m.runPlaybookSession(ctx, pb, session)
+		res.OK = res.OK && r.OK
+		res.Sessions = append(res.Sessions, r)
+	}
+	return res
+}
+
+// runPlaybookSession creates or finds a session of a Playbook, and runs its steps.
+func (m *Manager) runPlaybookSession(ctx context.Context, pb Playbook, session PlaybookSession) PlaybookSessionResult {
+	res := PlaybookSessionResult{Name: session.Name}
+
+	var s Screen
+	var err error
+	if session.Create {
+		s, err = m.newFromProfile(ctx, session.Name, pb.Profiles[session.Profile])
+	} else {
+		s, err = m.Get(session.Name)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	d, err := s.Driver(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer d.Close()
+
+	res.OK = true
+	for _, step := range session.Steps {
+		r := runPlaybookStep(ctx, s, d, step)
+		res.Steps = append(res.Steps, r)
+		if !r.OK {
+			res.OK = false
+			break
+		}
+	}
+	return res
+}
+
+// newFromProfile creates a screen with the settings of a Profile.
+func (m *Manager) newFromProfile(ctx context.Context, name string, p Profile) (Screen, error) {
+	shell := p.Shell
+	if len(shell) == 0 {
+		shell = []string{m.defaultShell()}
+	}
+	command, args, err := ExecOptions{Dir: p.Dir, Env: p.Env}.wrap(shell[0], shell[1:])
+	if err != nil {
+		return Screen{}, err
+	}
+	return m.New(ctx, name, append([]string{command}, args...)...)
+}
+
+// runPlaybookStep runs a single step of a Playbook.
+func runPlaybookStep(ctx context.Context, s Screen, d *Driver, step PlaybookStep) (res PlaybookStepResult) {
+	res.Step = step
+	start := time.Now()
+	defer func() { res.Duration = time.Since(start) }()
+
+	d.Timeout, _ = time.ParseDuration(step.Timeout) // Checked by Validate, zero means the default
+	var err error
+	switch {
+	case step.Cmd != "":
+		err = d.Cmd(step.Cmd).Err()
+	case step.Stuff != "":
+		err = d.Send(step.Stuff).Err()
+	case step.Expect != "":
+		if err = d.Expect(step.Expect).Err(); err == nil {
+			res.Output = d.Output()
+		}
+	case step.Prompt:
+		if err = d.WaitPrompt().Err(); err == nil {
+			res.Output = d.Output()
+		}
+	case step.Sleep != "":
+		wait, _ := time.ParseDuration(step.Sleep)
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(wait):
+		}
+	case step.Hardcopy:
+		res.Output, err = s.HardcopyString()
+	case step.Quit:
+		err = s.Quit()
+	default:
+		err = errors.New("step has no action")
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+	}
+	res.OK = err == nil
+	return
+}
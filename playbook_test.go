@@ -0,0 +1,36 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadPlaybook(t *testing.T) {
+	pb, err := ReadPlaybook(strings.NewReader(`{
+		"profiles": {"build": {"shell": ["/bin/bash"], "dir": "/srv/app", "env": ["CI=1"]}},
+		"sessions": [{
+			"name": "deploy", "create": true, "profile": "build",
+			"steps": [{"cmd": "git pull"}, {"expect": "up to date", "timeout": "1m"}, {"hardcopy": true}, {"quit": true}]
+		}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pb.Sessions) != 1 || len(pb.Sessions[0].Steps) != 4 || pb.Profiles["build"].Dir != "/srv/app" {
+		t.Errorf("got %+v", pb)
+	}
+
+	invalid := map[string]string{
+		"unknown field":   `{"sessions": [{"name": "a", "steps": [{"cmdd": "ls"}]}]}`,
+		"no action":       `{"sessions": [{"name": "a", "steps": [{"timeout": "1s"}]}]}`,
+		"two actions":     `{"sessions": [{"name": "a", "steps": [{"cmd": "ls", "quit": true}]}]}`,
+		"bad duration":    `{"sessions": [{"name": "a", "steps": [{"sleep": "soon"}]}]}`,
+		"missing profile": `{"sessions": [{"name": "a", "profile": "nope", "steps": []}]}`,
+		"invalid name":    `{"sessions": [{"name": "a/b", "steps": []}]}`,
+	}
+	for name, text := range invalid {
+		if _, err := ReadPlaybook(strings.NewReader(text)); err == nil {
+			t.Errorf("%s: accepted %s", name, text)
+		}
+	}
+}
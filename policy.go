@@ -0,0 +1,119 @@
+package screen
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrPolicyDenied is returned (as a *PolicyError) for commands the Manager's Policy doesn't allow.
+var ErrPolicyDenied = errors.New("denied by policy")
+
+// PolicyError is returned for a command the Manager's Policy refused, before it ran.
+type PolicyError struct {
+	Command string // The screen command, named like Metrics names it, i.e. "quit" or "ls"
+	Session string // The session it was aimed at, empty if none
+}
+
+func (e *PolicyError) Error() string {
+	if e.Session == "" {
+		return fmt.Sprintf("%q: %s", e.Command, ErrPolicyDenied)
+	}
+	return fmt.Sprintf("%q on %s: %s", e.Command, e.Session, ErrPolicyDenied)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyDenied
+}
+
+// Policy restricts which screen commands a Manager may run, and on which sessions. For example, a monitoring deployment
+// that must never quit or type into a screen could use:
+//
+//	m.Policy = &screen.Policy{Allow: []string{"ls", "version", "info", "hardcopy", "windows", "title"}}
+//
+// Commands are named like Metrics names them: the screen command or query (i.e. "stuff", or "windows" for
+// Screen.Windows), or "ls", "new", "attach" and "version" for screen's own flags. A Batch is judged by the commands in
+// it. Signals sent to a screen's processes (Screen.Signal, Terminate, Window.KillProcesses) count as "kill" on the
+// screen. The helpers the package runs on the host for itself (ps, cat, stat, ...) aren't screen commands, and are
+// always allowed.
+type Policy struct {
+	// Allow lists the commands that may run. Empty allows all of them, but those in Deny.
+	Allow []string
+	// Deny lists commands that may not run, even if Allow has them, i.e. "quit" and "kill".
+	Deny []string
+	// Sessions, if set, reports whether a session may be touched at all. It's given the session the way screen is,
+	// either a name or "<PID>.<name>".
+	Sessions func(session string) bool
+}
+
+// check returns a *PolicyError if the policy doesn't allow a command on the Manager's host. A nil policy allows all.
+func (p *Policy) check(name string, args []string) error {
+	if p == nil {
+		return nil
+	}
+	if path.Base(name) != "screen" {
+		return nil
+	}
+	cmd, session := describeCommand(name, args)
+
+	if session != "" && p.Sessions != nil && !p.Sessions(session) {
+		return &PolicyError{Command: cmd, Session: session}
+	}
+	cmds := []string{cmd}
+	if cmd == "eval" {
+		cmds = evalCommands(args)
+	}
+	for _, c := range cmds {
+		if !p.allows(c) {
+			return &PolicyError{Command: c, Session: session}
+		}
+	}
+	return nil
+}
+
+// checkSignal returns a *PolicyError if the policy doesn't allow signalling the processes of a screen, given as
+// "<PID>.<name>". A nil policy allows all.
+func (p *Policy) checkSignal(session string) error {
+	if p == nil {
+		return nil
+	}
+	if (p.Sessions != nil && !p.Sessions(session)) || !p.allows("kill") {
+		return &PolicyError{Command: "kill", Session: session}
+	}
+	return nil
+}
+
+// allows reports whether the policy lets a command run, not minding the session.
+func (p *Policy) allows(cmd string) bool {
+	for _, denied := range p.Deny {
+		if cmd == denied {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, allowed := range p.Allow {
+		if cmd == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// evalCommands returns the commands run by a "screen -X eval", the first word of each of its lines.
+func evalCommands(args []string) (res []string) {
+	for i, arg := range args {
+		if arg != "-X" || i+1 >= len(args) {
+			continue
+		}
+		for _, line := range args[i+2:] {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				res = append(res, strings.Trim(fields[0], `"'`))
+			}
+		}
+		break
+	}
+	return res
+}
@@ -0,0 +1,83 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                      fakeList,
+		"/usr/bin/screen -S 4242.a+b -X info": "",
+	}}
+	m := NewManagerWithRunner(r)
+	m.Policy = &Policy{Deny: []string{"quit", "kill", "stuff"}}
+
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.builtinTemplate("info"); err != nil {
+		t.Error(err)
+	}
+
+	var policyErr *PolicyError
+	if err = s.Quit(); !errors.As(err, &policyErr) || policyErr.Command != "quit" || policyErr.Session != "4242.a+b" {
+		t.Errorf("got %v, want quit denied", err)
+	}
+	if err = s.SourceLines([]string{"title x", "stuff rm -rf /"}); !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("got %v, want stuff in a batch denied", err)
+	}
+	if ran := strings.Join(r.ran, "\n"); strings.Contains(ran, "quit") || strings.Contains(ran, "eval") {
+		t.Errorf("denied commands ran:\n%s", ran)
+	}
+
+	// Allowed commands only, on allowed sessions only
+	m.Policy = &Policy{Allow: []string{"ls", "info"}, Sessions: func(session string) bool { return session != "deploy" }}
+	if err = s.builtinTemplate("info"); err != nil {
+		t.Error(err)
+	}
+	if err = s.SetTitle("x"); !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("got %v, want title denied", err)
+	}
+	if _, err = m.Get("deploy"); !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("got %v, want the session denied", err)
+	}
+}
+
+func TestPolicyExample(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                            fakeList,
+		"/usr/bin/screen -S 4242.a+b -Q windows":    "0 bash  1*$ logs",
+		"/usr/bin/screen -S 4242.a+b -p 1 -Q title": "logs",
+	}}
+	m := NewManagerWithRunner(r)
+	m.Policy = &Policy{Allow: []string{"ls", "version", "info", "hardcopy", "windows", "title"}}
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.Windows(); err != nil {
+		t.Error(err)
+	}
+	if _, err = s.Window(1).Title(); err != nil {
+		t.Error(err)
+	}
+	if err = s.Stuff("rm -rf /"); !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("got %v, want stuff denied", err)
+	}
+	if err = s.Terminate(context.Background(), StopShell); !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("got %v, want signals denied", err)
+	}
+
+	// Signals are checked against the screen they're sent to
+	m.Policy = &Policy{Sessions: func(session string) bool { return session != "4242.a+b" }}
+	var policyErr *PolicyError
+	if err = s.Window(1).KillProcesses(syscall.SIGTERM); !errors.As(err, &policyErr) || policyErr.Command != "kill" || policyErr.Session != "4242.a+b" {
+		t.Errorf("got %v, want kill on 4242.a+b denied", err)
+	}
+}
@@ -0,0 +1,36 @@
+package screen
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// preflightScript exits non-zero unless $1 resolves to an executable file, like exec.LookPath does.
+const preflightScript = `p=$(command -v -- "$1") && test -f "$p" && test -x "$p"`
+
+// preflight checks that shell, the program New starts a screen with, resolves to an executable file on the Manager's
+// host. Otherwise screen would start, and die right away.
+func (m *Manager) preflight(ctx context.Context, shell string) error {
+	if m.isLocal() {
+		path, err := exec.LookPath(shell)
+		if err != nil {
+			return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: fmt.Errorf("shell %q: %w", shell, err)}
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: fmt.Errorf("shell %q: %s isn't a file", shell, path)}
+		}
+		return nil
+	}
+
+	if _, stderr, err := m.run(ctx, "sh", "-c", preflightScript, "sh", shell); err != nil {
+		text := strings.TrimSpace(string(stderr))
+		if text == "" {
+			text = "executable file not found"
+		}
+		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: fmt.Errorf("shell %q: %s", shell, text)}
+	}
+	return nil
+}
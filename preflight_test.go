@@ -0,0 +1,36 @@
+package screen
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	if err := local.preflight(context.Background(), "sh"); err != nil {
+		t.Errorf("sh: %v", err)
+	}
+	for _, shell := range []string{"/nonexistent/shell", os.TempDir()} {
+		if err := local.preflight(context.Background(), shell); err == nil || !strings.Contains(err.Error(), shell) {
+			t.Errorf("%s: got %v", shell, err)
+		}
+	}
+}
+
+func TestRunnerPreflight(t *testing.T) {
+	r := &fakeRunner{
+		outputs: map[string]string{"screen -ls race": ""},
+		exits:   map[string]int{"screen -ls race": 1, "sh -c " + preflightScript + " sh /nonexistent": 1},
+	}
+	m := NewManagerWithRunner(r)
+	m.DefaultShell = "/nonexistent"
+	m.Preflight = true
+
+	if _, err := m.New(context.Background(), "race"); err == nil || !strings.Contains(err.Error(), "/nonexistent") {
+		t.Errorf("got %v", err)
+	}
+	if strings.Contains(strings.Join(r.ran, "\n"), "-dmS") {
+		t.Error("screen was started anyway")
+	}
+}
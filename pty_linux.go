@@ -0,0 +1,85 @@
+//go:build linux
+// +build linux
+
+package screen
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// winsize mirrors struct winsize from <sys/ioctl.h>.
+type winsize struct {
+	Rows, Cols, X, Y uint16
+}
+
+func ioctl(f *os.File, req uintptr, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// startPTY starts cmd with a new pseudo terminal as its controlling terminal, and returns the master side.
+func startPTY(cmd *exec.Cmd, cols, rows uint16) (*os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlock the slave, and find out its number
+	var unlock int32
+	if err = ioctl(master, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, err
+	}
+	var n uint32
+	if err = ioctl(master, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	if err = setSize(master, cols, rows); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	slave, err := os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+	defer slave.Close()
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err = cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	return master, nil
+}
+
+// setSize sets the window size of a terminal.
+func setSize(f *os.File, cols, rows uint16) error {
+	ws := winsize{Rows: rows, Cols: cols}
+	return ioctl(f, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws)))
+}
+
+// getSize gets the window size of a terminal.
+func getSize(f *os.File) (cols, rows uint16, err error) {
+	var ws winsize
+	err = ioctl(f, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	return ws.Cols, ws.Rows, err
+}
+
+// notifyResize relays terminal resize signals to c.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
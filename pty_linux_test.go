@@ -0,0 +1,32 @@
+package screen
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestStartPTY(t *testing.T) {
+	cmd := exec.Command("stty", "size")
+	pty, err := startPTY(cmd, 100, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pty.Close()
+
+	// The slave hangs up once stty exits, ending the read with an error
+	out, _ := io.ReadAll(pty)
+	cmd.Wait()
+
+	if got := strings.TrimSpace(string(out)); got != "30 100" {
+		t.Errorf("got size %q, want %q", got, "30 100")
+	}
+
+	if err := setSize(pty, 120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if cols, rows, err := getSize(pty); err != nil || cols != 120 || rows != 40 {
+		t.Errorf("got %dx%d (%v), want 120x40", cols, rows, err)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+// +build !linux
+
+package screen
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errNoPTY = errors.New("pseudo terminals are only supported on linux")
+
+func startPTY(cmd *exec.Cmd, cols, rows uint16) (*os.File, error) {
+	return nil, errNoPTY
+}
+
+func setSize(f *os.File, cols, rows uint16) error {
+	return errNoPTY
+}
+
+func getSize(f *os.File) (cols, rows uint16, err error) {
+	return 0, 0, errNoPTY
+}
+
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,170 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// WindowEntry is a window as listed by Screen.Windows.
+type WindowEntry = screenparse.WindowEntry
+
+// Query runs a screen command that reports something, like "windows", "title" or "info", and returns screen's reply
+// ("screen -Q", screen 4.1 and newer). Replies are cached per screen for the Manager's Defaults.QueryTTL, until a
+// command is sent to the screen.
+func (s Screen) Query(command string, args ...string) (string, error) {
+	return s.query(-1, command, args...)
+}
+
+// Windows lists the screen's windows.
+func (s Screen) Windows() ([]WindowEntry, error) {
+	out, err := s.Query("windows")
+	if err != nil {
+		return nil, err
+	}
+	return screenparse.ParseWindows(out), nil
+}
+
+// WindowCount returns how many windows the screen has.
+func (s Screen) WindowCount() (int, error) {
+	windows, err := s.Windows()
+	return len(windows), err
+}
+
+// HasWindow reports whether the screen has a window with the given number or title, i.e. "2" or "logs". A title
+// that looks like a number matches too.
+func (s Screen) HasWindow(titleOrNumber string) (bool, error) {
+	windows, err := s.Windows()
+	if err != nil {
+		return false, err
+	}
+	for _, w := range windows {
+		if w.Title == titleOrNumber || strconv.Itoa(w.Number) == titleOrNumber {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SelectWindowByTitle makes the window with the given title the screen's current one, so commands and stuffing
+// without a window go to it. Window numbers shift as windows come and go, titles don't. If several windows have the
+// title, the one with the lowest number is selected.
+func (s Screen) SelectWindowByTitle(title string) error {
+	windows, err := s.Windows()
+	if err != nil {
+		return err
+	}
+	for _, w := range windows {
+		if w.Title == title {
+			return s.builtinTemplateArgs("select", strconv.Itoa(w.Number))
+		}
+	}
+	return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("no window titled " + strconv.Quote(title))}
+}
+
+// Title returns the title of the window.
+func (w Window) Title() (string, error) {
+	out, err := w.Screen.query(w.Number, "title")
+	return strings.TrimRight(out, "\r\n"), err
+}
+
+// query runs a query in a window of the screen, or its current window if window is negative.
+func (s Screen) query(window int, command string, args ...string) (string, error) {
+	params := []string{"-S", s.target()}
+	if window >= 0 {
+		params = append(params, "-p", strconv.Itoa(window))
+	}
+	params = append(append(params, "-Q", command), args...)
+
+	m := s.m()
+	key := strings.Join(params[2:], "\x00")
+	if out, ok := m.queries.get(s.target(), key, m.queryTTL()); ok {
+		return out, nil
+	}
+
+	out, _, err := m.run(context.Background(), screenExec, params...)
+	if err != nil {
+		return "", err
+	}
+	m.queries.put(s.target(), key, string(out))
+	return string(out), nil
+}
+
+// queryTTL returns the Manager's Defaults.QueryTTL, or its default. Zero means no caching.
+func (m *Manager) queryTTL() time.Duration {
+	switch {
+	case m.Defaults.QueryTTL < 0:
+		return 0
+	case m.Defaults.QueryTTL == 0:
+		return time.Millisecond * 250
+	}
+	return m.Defaults.QueryTTL
+}
+
+// queryCache holds replies of "screen -Q", per screen target and query.
+type queryCache struct {
+	mutex   sync.Mutex
+	screens map[string]map[string]cachedQuery
+}
+
+type cachedQuery struct {
+	out string
+	at  time.Time
+}
+
+// get returns a cached reply, unless it's older than ttl.
+func (c *queryCache) get(target, key string, ttl time.Duration) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	q, ok := c.screens[target][key]
+	if !ok || time.Since(q.at) >= ttl {
+		return "", false
+	}
+	return q.out, true
+}
+
+// put caches a reply.
+func (c *queryCache) put(target, key, out string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.screens == nil {
+		c.screens = map[string]map[string]cachedQuery{}
+	}
+	if c.screens[target] == nil {
+		c.screens[target] = map[string]cachedQuery{}
+	}
+	c.screens[target][key] = cachedQuery{out: out, at: time.Now()}
+}
+
+// invalidate forgets the cached replies of a screen, since a command may have changed them.
+func (c *queryCache) invalidate(target string) {
+	c.mutex.Lock()
+	delete(c.screens, target)
+	c.mutex.Unlock()
+}
+
+// commandSent notes a command sent to a screen: its cached replies are forgotten, and it counts as active for ReapIdle.
+func (m *Manager) commandSent(name string, args []string) {
+	if name != screenExec {
+		return
+	}
+	var target string
+	for i, arg := range args {
+		switch {
+		case arg == "-S" && i+1 < len(args):
+			target = args[i+1]
+		case arg == "-X":
+			m.queries.invalidate(target)
+			m.activity.Store(target, time.Now())
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package screen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryCache(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":             fakeList,
+		screen + "-Q windows":        "0$ bash  1*$ vim\n",
+		screen + "-p 1 -Q title":     "vim\n",
+		screen + "-X title 'editor'": "",
+		screen + "-X stuff ls":       "",
+	}}
+	m := NewManagerWithRunner(r)
+	m.Defaults.QueryTTL = time.Hour
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := func() (n int) {
+		for _, line := range r.ran {
+			if line == screen+"-Q windows" {
+				n++
+			}
+		}
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		windows, err := s.Windows()
+		if err != nil || len(windows) != 2 || windows[1].Title != "vim" {
+			t.Fatalf("got %+v, %v", windows, err)
+		}
+	}
+	if n := queries(); n != 1 {
+		t.Errorf("queried %d times, want 1", n)
+	}
+
+	// Commands may change what queries say
+	if err = s.Stuff("ls"); err != nil {
+		t.Fatal(err)
+	}
+	s.Windows()
+	if n := queries(); n != 2 {
+		t.Errorf("queried %d times after a command, want 2", n)
+	}
+
+	if title, err := s.Window(1).Title(); err != nil || title != "vim" {
+		t.Errorf("got %q, %v", title, err)
+	}
+
+	m.Defaults.QueryTTL = -1
+	s.Windows()
+	s.Windows()
+	if n := queries(); n != 4 {
+		t.Errorf("queried %d times without caching, want 4", n)
+	}
+}
+
+func TestHasWindow(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                         fakeList,
+		"/usr/bin/screen -S 4242.a+b -Q windows": "0$ bash  3*$ build logs",
+	}})
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := s.WindowCount(); n != 2 || err != nil {
+		t.Errorf("got %d, %v windows", n, err)
+	}
+	for window, want := range map[string]bool{"0": true, "3": true, "1": false, "build logs": true, "build": false} {
+		if got, err := s.HasWindow(window); got != want || err != nil {
+			t.Errorf("%q: got %v, %v", window, got, err)
+		}
+	}
+}
+
+func TestSelectWindowByTitle(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":       fakeList,
+		screen + "-Q windows":  "0$ bash  3*$ logs  5$ logs",
+		screen + "-X select 3": "",
+	}}
+	m := NewManagerWithRunner(r)
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.SelectWindowByTitle("logs"); err != nil {
+		t.Error(err)
+	}
+	if err = s.SelectWindowByTitle("vim"); err == nil {
+		t.Error("selected a window that doesn't exist")
+	}
+}
@@ -0,0 +1,148 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// ErrQuotaExceeded is returned (as a *QuotaError) when New or Log would go over one of the Manager's Limits.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaError is returned when New or Log would go over one of the Manager's Limits.
+type QuotaError struct {
+	Limit   string // What's limited, i.e. "sessions", `sessions tagged "ci"` or "log size"
+	Current int64  // How much there is already
+	Max     int64
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: %s at %d, limit is %d", ErrQuotaExceeded, e.Limit, e.Current, e.Max)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// Limits keep a runaway caller from exhausting the Manager's host. Zero fields don't limit anything.
+type Limits struct {
+	// MaxSessions is how many live sessions New lets the host have. screen only lists the sessions of the user the
+	// Manager runs as, so this is a limit per user.
+	MaxSessions int
+	// MaxSessionsPerTag is how many live sessions New lets share any tag, as Manager.Tags gives them.
+	MaxSessionsPerTag int
+	// MaxLogSize is how many bytes a logfile of Screen.Log may grow to. Log refuses to append to a bigger one, and
+	// switches logging off once the logfile passes it, which finishes it like any other (see Manager.OnLogFinished).
+	MaxLogSize int64
+}
+
+// limitsSet reports whether New has to count sessions.
+func (l Limits) limitsSet() bool {
+	return l.MaxSessions > 0 || l.MaxSessionsPerTag > 0
+}
+
+// checkSessionQuota returns a *QuotaError if New making a screen of the given name would go over the Manager's Limits.
+func (m *Manager) checkSessionQuota(name string) error {
+	screens, states, err := m.listSessions()
+	if err != nil {
+		return err
+	}
+	var live []Screen
+	for i, s := range screens {
+		if states[i] != screenparse.StateDead {
+			live = append(live, s)
+		}
+	}
+
+	if max := m.Limits.MaxSessions; max > 0 && len(live) >= max {
+		return &QuotaError{Limit: "sessions", Current: int64(len(live)), Max: int64(max)}
+	}
+	if max := m.Limits.MaxSessionsPerTag; max > 0 && m.Tags != nil {
+		counts := map[string]int{}
+		for _, s := range live {
+			for _, tag := range m.Tags(s) {
+				counts[tag]++
+			}
+		}
+		for _, tag := range m.Tags(Screen{Name: name, Mutex: m.mutex(name), manager: m}) {
+			if counts[tag] >= max {
+				return &QuotaError{Limit: "sessions tagged " + strconv.Quote(tag), Current: int64(counts[tag]), Max: int64(max)}
+			}
+		}
+	}
+	return nil
+}
+
+// checkLogQuota returns a *QuotaError if Log appending to path would start out over the Manager's MaxLogSize.
+func (m *Manager) checkLogQuota(path string, append bool) error {
+	if m.Limits.MaxLogSize <= 0 || !append || path == "" {
+		return nil
+	}
+	size, err := m.fileSize(path)
+	if err != nil {
+		return nil // Nothing to append to yet
+	}
+	if size >= m.Limits.MaxLogSize {
+		return &QuotaError{Limit: "log size", Current: size, Max: m.Limits.MaxLogSize}
+	}
+	return nil
+}
+
+// limitLog switches logging of the screen off once its logfile passes the Manager's MaxLogSize, checking as often as
+// screen flushes it. It stops when the screen logs elsewhere, or its background work is stopped.
+func (m *Manager) limitLog(s Screen, path string, flushInterval uint) {
+	if m.Limits.MaxLogSize <= 0 || path == "" {
+		return
+	}
+	ctx, cancel, err := m.bind(context.Background(), s.Name)
+	if err != nil {
+		return
+	}
+	interval := time.Duration(flushInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second * 10
+	}
+
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if current, _ := m.logs.Load(s.Name); current != path {
+				return
+			}
+			if size, err := m.fileSize(path); err == nil && size > m.Limits.MaxLogSize {
+				s.Log("", false, flushInterval)
+				return
+			}
+		}
+	}()
+}
+
+// fileSize returns the size of a file on the Manager's host.
+func (m *Manager) fileSize(path string) (int64, error) {
+	if m.isLocal() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		return info.Size(), nil
+	}
+
+	out, _, err := m.run(context.Background(), "stat", "-c", "%s", path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
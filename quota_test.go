@@ -0,0 +1,78 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionQuota(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{"screen -ls": fakeList}}
+	m := NewManagerWithRunner(r)
+	m.DefaultShell = "/bin/sh"
+	m.Tags = func(s Screen) []string { return strings.Fields(s.Name)[:1] }
+
+	m.Limits = Limits{MaxSessions: 3}
+	var quotaErr *QuotaError
+	if _, err := m.New(context.Background(), "more"); !errors.As(err, &quotaErr) || quotaErr.Limit != "sessions" {
+		t.Errorf("got %v, want the session limit", err)
+	}
+
+	m.Limits = Limits{MaxSessionsPerTag: 2}
+	if _, err := m.New(context.Background(), "deploy asia"); !errors.As(err, &quotaErr) || quotaErr.Limit != `sessions tagged "deploy"` {
+		t.Errorf("got %v, want the tag limit", err)
+	}
+	if _, err := m.New(context.Background(), "build"); errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("got %v for an untagged screen", err)
+	}
+	if strings.Count(strings.Join(r.ran, "\n"), "-dmS") != 1 {
+		t.Errorf("screens over the limits were started:\n%s", strings.Join(r.ran, "\n"))
+	}
+}
+
+func TestLogQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "screenlog.0")
+	if err := os.WriteFile(path, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{Limits: Limits{MaxLogSize: 10}}
+	if err := m.checkLogQuota(path, true); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("got %v appending to a full log", err)
+	}
+	if err := m.checkLogQuota(path, false); err != nil {
+		t.Errorf("got %v overwriting a full log", err)
+	}
+
+	// A logfile growing past the limit is finished
+	const screen = "/usr/bin/screen -S 4242.a+b -X "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":           fakeList,
+		"stat -c %s /srv/log":      "11\n",
+		screen + "logfile ":        "",
+		screen + "logfile flush 1": "",
+		screen + "log off":         "",
+	}}
+	m = NewManagerWithRunner(r)
+	m.Limits.MaxLogSize = 10
+	finished := make(chan string, 1)
+	m.OnLogFinished = func(s Screen, path string) { finished <- path }
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.logs.Store(s.Name, "/srv/log")
+	m.limitLog(s, "/srv/log", 1)
+
+	select {
+	case got := <-finished:
+		if got != "/srv/log" {
+			t.Errorf("finished %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("logging wasn't switched off")
+	}
+}
@@ -0,0 +1,143 @@
+package screen
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// IdlePolicy is which sessions ReapIdle reclaims, and how.
+type IdlePolicy struct {
+	// TTL is how long a session may be idle before it's terminated. 0 keeps sessions forever, unless TagTTL has one
+	// of their tags.
+	TTL time.Duration
+	// TagTTL overrides TTL for sessions with a tag (see Manager.Tags), i.e. {"ci": time.Hour}. If a session has
+	// several, the longest TTL applies, and 0 keeps it forever.
+	TagTTL map[string]time.Duration
+	// Stop is how idle sessions are terminated, see Terminate. Without signals, StopShell is used.
+	Stop StopPolicy
+}
+
+// ttl returns how long a session with the given tags may be idle, 0 for forever.
+func (p IdlePolicy) ttl(tags []string) time.Duration {
+	ttl, tagged := time.Duration(0), false
+	for _, tag := range tags {
+		if d, ok := p.TagTTL[tag]; ok {
+			if d <= 0 {
+				return 0
+			}
+			if d > ttl {
+				ttl = d
+			}
+			tagged = true
+		}
+	}
+	if !tagged {
+		return p.TTL
+	}
+	return ttl
+}
+
+// stop returns the policy idle sessions are terminated with.
+func (p IdlePolicy) stop() StopPolicy {
+	if len(p.Stop.Signals) == 0 && !p.Stop.FinallyQuit {
+		return StopShell
+	}
+	return p.Stop
+}
+
+// Reaped is a session ReapIdle terminated, or tried to.
+type Reaped struct {
+	Screen Screen
+	Idle   time.Duration // How long it had been idle for
+	Err    error         // Why terminating it failed, nil if it's gone
+}
+
+// ReapIdle checks the Manager's sessions every interval until ctx is done, terminates those that were idle for
+// longer than policy allows, and sends each to reaped (which may be nil). A session is idle while nobody is attached
+// to it, the Manager sends it no commands, and it outputs nothing, which is only seen while it's captured (see
+// Throughput). Sessions start out busy when ReapIdle first sees them. It returns when ctx is done, or listing fails.
+func (m *Manager) ReapIdle(ctx context.Context, interval time.Duration, policy IdlePolicy, reaped chan<- Reaped) error {
+	ctx, cancel, err := m.bind(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	busy := map[string]time.Time{} // When each session was last seen busy, by "<PID>.<name>"
+	for {
+		if err = m.reapIdle(ctx, time.Now(), policy, busy, reaped); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// reapIdle is a single round of ReapIdle, at now.
+func (m *Manager) reapIdle(ctx context.Context, now time.Time, policy IdlePolicy, busy map[string]time.Time, reaped chan<- Reaped) error {
+	screens, states, err := m.listSessions()
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for i, s := range screens {
+		if states[i] == screenparse.StateDead {
+			continue
+		}
+		key := s.target()
+		seen[key] = true
+
+		last, known := busy[key]
+		if !known || states[i] == screenparse.StateAttached {
+			last = now
+		}
+		if v, ok := m.activity.Load(key); ok && v.(time.Time).After(last) {
+			last = v.(time.Time)
+		}
+		if out := s.Throughput().LastOutput; out.After(last) {
+			last = out
+		}
+		busy[key] = last
+
+		var tags []string
+		if m.Tags != nil {
+			tags = m.Tags(s)
+		}
+		ttl := policy.ttl(tags)
+		if ttl <= 0 || now.Sub(last) < ttl {
+			continue
+		}
+
+		r := Reaped{Screen: s, Idle: now.Sub(last), Err: s.Terminate(ctx, policy.stop())}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if r.Err == nil {
+			delete(busy, key)
+			m.activity.Delete(key)
+		}
+		if reaped != nil {
+			select {
+			case reaped <- r:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	// Forget sessions that are gone
+	for key := range busy {
+		if !seen[key] {
+			delete(busy, key)
+			m.activity.Delete(key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package screen
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReapIdle(t *testing.T) {
+	r := &stubbornRunner{fakeRunner: &fakeRunner{outputs: map[string]string{
+		"screen -ls": "There are screens on:\n" +
+			"\t4242.a+b\t(Detached)\n" +
+			"\t4243.keep\t(Detached)\n" +
+			"2 Sockets in /run/screen/S-root.\n",
+		"screen -ls a+b":                       fakeList,
+		"ps --no-headers --ppid 4242 -o pid:1": "4300\n",
+		"ps --no-headers --ppid 4300 -o pid:1": "",
+		"kill -1 4300":                         "",
+	}}, diesOf: "-1"}
+	m := NewManagerWithRunner(r)
+	m.Defaults.OutputPoll = time.Millisecond
+	m.Tags = func(s Screen) []string { return []string{s.Name} }
+	policy := IdlePolicy{
+		TTL:    time.Hour,
+		TagTTL: map[string]time.Duration{"keep": 0},
+		Stop:   StopPolicy{Signals: []syscall.Signal{syscall.SIGHUP}, Waits: []time.Duration{time.Millisecond}},
+	}
+
+	start := time.Now()
+	busy := map[string]time.Time{}
+	reaped := make(chan Reaped, 2)
+	round := func(after time.Duration) {
+		if err := m.reapIdle(context.Background(), start.Add(after), policy, busy, reaped); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	round(0)
+	m.activity.Store("4242.a+b", start.Add(90*time.Minute)) // A command sent in the meantime
+	round(2 * time.Hour)
+	if len(reaped) != 0 {
+		t.Fatalf("reaped %+v while busy", <-reaped)
+	}
+
+	round(3 * time.Hour)
+	if len(reaped) != 1 {
+		t.Fatalf("reaped %d sessions, want 1", len(reaped))
+	}
+	if got := <-reaped; got.Screen.Name != "a+b" || got.Idle != 90*time.Minute || got.Err != nil {
+		t.Errorf("got %+v", got)
+	}
+}
@@ -0,0 +1,70 @@
+package screen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+)
+
+// RestoreScrollback replays what an earlier screen displayed into s, i.e. a screen recreated after a reboot, so
+// whoever attaches still sees the history of its predecessor. transcript is either raw output (a hardcopy, logfile
+// or Export's Scrollback), or a transcript written by a Recorder or asciinema, of which only the output is replayed.
+//
+// The output is written to the window by a command screen runs, not typed into the shell, so nothing ends up in the
+// shell's history. It returns once everything was written.
+func RestoreScrollback(ctx context.Context, s Screen, transcript io.Reader) error {
+	b, err := scrollbackBytes(transcript)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+
+	name, err := s.m().tempFile()
+	if err != nil {
+		return err
+	}
+	defer s.m().remove(name)
+	if err = s.m().writeFile(name, b); err != nil {
+		return err
+	}
+
+	code, err := s.ExecWait(ctx, FdPat{Pipe: true}, "cat", name)
+	if err == nil && code != 0 {
+		err = fmt.Errorf("replaying scrollback: cat exited with %d", code)
+	}
+	return err
+}
+
+// scrollbackBytes returns what to write to a window to show transcript, see RestoreScrollback. Bare newlines, as in
+// hardcopies, become "\r\n", since the window would only move down a line otherwise.
+func scrollbackBytes(transcript io.Reader) ([]byte, error) {
+	br := bufio.NewReader(transcript)
+	start, _ := br.Peek(1)
+
+	var b []byte
+	if len(start) == 1 && start[0] == '{' {
+		t, err := ReadTranscript(br)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		for _, e := range t {
+			if e.Kind == TranscriptOutput {
+				buf.WriteString(e.Data)
+			}
+		}
+		b = buf.Bytes()
+	} else {
+		var err error
+		if b, err = io.ReadAll(br); err != nil {
+			return nil, err
+		}
+	}
+
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n")), nil
+}
@@ -0,0 +1,20 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScrollbackBytes(t *testing.T) {
+	for in, want := range map[string]string{
+		"$ make\nok\r\n$ ": "$ make\r\nok\r\n$ ",
+		"":                 "",
+		`{"time":"2024-01-01T00:00:00Z","kind":"input","data":"ls\n"}` + "\n" +
+			`{"time":"2024-01-01T00:00:01Z","kind":"output","data":"a  b\r\n"}` + "\n": "a  b\r\n",
+	} {
+		got, err := scrollbackBytes(strings.NewReader(in))
+		if err != nil || string(got) != want {
+			t.Errorf("%q: got %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
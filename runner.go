@@ -0,0 +1,66 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// Runner runs commands on a Manager's host. Every command the package runs goes through one, so implementing it is
+// how to add a backend (i.e. SSH), a dry-run mode, fault injection, or a fake for tests that don't need screen.
+type Runner interface {
+	// Run runs name with args, and returns what it wrote to stdout and stderr. A command that ran but failed should
+	// return an *exec.ExitError (or anything else), along with its output.
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// Commander is implemented by Runners that can also build an *exec.Cmd, which is needed to stream a command's input
+// or output: Capture, LogTo, Record, ExecPipe, Attach, and writing files on hosts that aren't local.
+type Commander interface {
+	Runner
+	Command(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// ErrNoCommander is returned by features that need to stream a command, when the Manager's Runner isn't a Commander.
+var ErrNoCommander = errors.New("runner can't stream commands")
+
+// ExecRunner runs commands with os/exec, optionally through another command, i.e. "docker exec <container>". It's
+// the Runner of every Manager this package makes.
+type ExecRunner struct {
+	Prefix    []string // Prepended to every command, empty means run locally
+	TTYPrefix []string // Like Prefix, but for commands that need a terminal passed through; empty means Prefix
+}
+
+// Run runs a command, see Runner.
+func (r ExecRunner) Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error) {
+	var outBuf, errBuf bytes.Buffer
+	cmd := r.Command(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &outBuf, &errBuf
+	err = cmd.Run()
+	return outBuf.Bytes(), errBuf.Bytes(), err
+}
+
+// Command builds a command, see Commander.
+func (r ExecRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return prefixedCommand(ctx, r.Prefix, name, args...)
+}
+
+// TTYCommand builds a command like Command, but asks the prefix to pass a terminal through to it.
+func (r ExecRunner) TTYCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if len(r.TTYPrefix) == 0 {
+		return r.Command(ctx, name, args...)
+	}
+	return prefixedCommand(ctx, r.TTYPrefix, name, args...)
+}
+
+// prefixedCommand builds a command running name through prefix, i.e. "docker exec web".
+func prefixedCommand(ctx context.Context, prefix []string, name string, args ...string) *exec.Cmd {
+	if len(prefix) == 0 {
+		return exec.CommandContext(ctx, name, args...)
+	}
+
+	params := append([]string{}, prefix[1:]...)
+	params = append(params, name)
+	return exec.CommandContext(ctx, prefix[0], append(params, args...)...)
+}
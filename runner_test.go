@@ -0,0 +1,141 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeRunner answers commands from a table keyed by the command line, and records what it ran.
+type fakeRunner struct {
+	outputs map[string]string
+	exits   map[string]int // Exit codes of commands that fail, the output still comes from outputs
+	ran     []string
+}
+
+func (r *fakeRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	r.ran = append(r.ran, line)
+
+	out, ok := r.outputs[line]
+	if code, failed := r.exits[line]; failed {
+		err := exec.Command("sh", "-c", "exit "+strconv.Itoa(code)).Run()
+		return []byte(out), nil, err
+	}
+	if !ok {
+		return nil, []byte("unexpected command " + line), &exec.ExitError{}
+	}
+	return []byte(out), nil, nil
+}
+
+const fakeList = "There are screens on:\n" +
+	"\t4250.deploy europe-west 1\t(Detached)\n" +
+	"\t4251.deploy europe-west 10\t(Detached)\n" +
+	"\t4242.a+b\t(Attached)\n" +
+	"3 Sockets in /run/screen/S-root.\n"
+
+func TestRunnerGet(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls deploy europe-west 1": fakeList,
+		"screen -ls a+b":                  fakeList,
+	}}
+	m := NewManagerWithRunner(r)
+
+	s, err := m.Get("deploy europe-west 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Process.Pid != 4250 {
+		t.Errorf("got PID %d, want 4250", s.Process.Pid)
+	}
+
+	if s, err = m.Get("a+b"); err != nil || s.Process.Pid != 4242 {
+		t.Errorf("got %+v, %v for a name with regexp metacharacters", s, err)
+	}
+}
+
+func TestRunnerListSessions(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": fakeList}})
+
+	screens, err := m.ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(screens) != 3 || screens[1].Name != "deploy europe-west 10" {
+		t.Errorf("got %+v", screens)
+	}
+}
+
+func TestRunnerListSessionsLocalized(t *testing.T) {
+	r := &fakeRunner{
+		outputs: map[string]string{"screen -ls": "Keine Sockets gefunden in /run/screen/S-root.\n"},
+		exits:   map[string]int{"screen -ls": 1, "screen -ls -q": 9},
+	}
+	m := NewManagerWithRunner(r)
+
+	screens, err := m.ListSessions()
+	if err != nil || len(screens) != 0 {
+		t.Errorf("got %+v, %v, want no screens", screens, err)
+	}
+
+	r.exits["screen -ls -q"] = 10 // There are sockets after all, so the output really is garbage
+	if _, err = m.ListSessions(); !errors.Is(err, ErrUnparseable) {
+		t.Errorf("got %v, want ErrUnparseable", err)
+	}
+}
+
+func TestRunnerStuffReturnGetOutputCanceled(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b -X "
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                      fakeList,
+		"mktemp -d -t go-gnu-screen-XXXXXXXX": "/tmp/spool\n",
+		"mktemp -p /tmp/spool":                "/tmp/spool/out\n",
+		"test -e /tmp/spool/out":              "",
+		screen + "logfile /tmp/spool/out":     "",
+		screen + "logfile flush 1":            "",
+		screen + "log on":                     "",
+		screen + "log off":                    "",
+		screen + "logfile screenlog.%n":       "",
+		"rm -f /tmp/spool/out":                "",
+	}}
+	m := NewManagerWithRunner(r)
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = s.StuffReturnGetOutput(ctx, "true"); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	ran := strings.Join(r.ran, "\n")
+	for _, want := range []string{screen + "log on", screen + "log off", screen + "logfile screenlog.%n", "rm -f /tmp/spool/out"} {
+		if !strings.Contains(ran, want) {
+			t.Errorf("%q wasn't run", want)
+		}
+	}
+	if strings.Contains(ran, "-X stuff") {
+		t.Error("stuffed after cancelation")
+	}
+}
+
+func TestRunnerGetAllChurn(t *testing.T) {
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": "There are screens on:\n" +
+		"\t4250.deploy\t(Detached)\n" +
+		"\t4250.deploy\t(Detached)\n" +
+		"\tgarbage\n" +
+		"\n" +
+		"\t4251.build\t(Attached)\n" +
+		"Remove dead screens with 'screen -wipe'.\n" +
+		"2 Sockets in /run/screen/S-root.\n"}})
+
+	screens := m.GetAll()
+	if len(screens) != 2 || screens[0].Name != "deploy" || screens[1].Name != "build" {
+		t.Errorf("got %+v", screens)
+	}
+}
@@ -5,105 +5,164 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"os/user"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
 )
 
 // Screen represents a GNU screen instance.
 type Screen struct {
 	Name    string
 	Mutex   *sync.Mutex
-	Process *os.Process
+	Process *os.Process // The SCREEN server itself, see ShellPID for the process running inside
+
+	manager   *Manager // Manager the screen was retrieved through, nil means local
+	startTime uint64   // When Process started, to tell it apart from a later process reusing its PID; 0 if unknown
 }
 
 const screenExec = "/usr/bin/screen"
 
-var screenDir = "/var/run/screen"
-var username = ""
-var mutexes sync.Map
+var username = "" // Of the current user, for finding the socket directory
 
-// init will get called automatically when the library is used
-func init() {
-	// Check if new screendir is defined
-	var isSet bool
-	if screenDir, isSet = os.LookupEnv("SCREENDIR"); !isSet {
-		screenDir = "/run/screen"
-	}
+// New will create a screen with the given name. It waits until the system starts the screen, then returns. Specify shell, i.e. "bash",
+// or leave it out to use the caller's $SHELL, falling back to "/bin/sh". An empty name gets one from GenerateName.
+// The shell is given as separate arguments, i.e. "bash", "--login", which screen passes on as they are; a single
+// "bash --login" would be taken as the name of the program.
+// If the screen exists already, ErrSessionExists is returned, and if it dies right away, ErrStartupFailed.
+func New(ctx context.Context, name string, shell ...string) (s Screen, err error) {
+	return local.New(ctx, name, shell...)
+}
 
-	// Stat screendir
-	_, err := os.Stat(screenDir)
-	if err != nil {
-		panic(err)
-	}
+// Get will retrieve an existing screen, and return a Screen struct. If no screen is found, ErrNotExist type is returned.
+// Only the whole name matches, see GetPrefix for matching the start of names.
+func Get(name string) (s Screen, err error) {
+	return local.Get(name)
+}
 
-	// Get user
-	u, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-	username = u.Username
+// GetAll returns all existing screens.
+func GetAll() (res []Screen) {
+	return local.GetAll()
+}
+
+// ListSessions returns all existing screens. Unlike GetAll, it reports why screens couldn't be listed: ErrNotInstalled,
+// ErrSocketDirPermission or ErrUnparseable (check with errors.Is). No screens at all isn't an error.
+func ListSessions() ([]Screen, error) {
+	return local.ListSessions()
 }
 
-// New will create a screen with the given name. It waits until the system starts the screen, then returns. Specify shell, i.e. "bash"
-func New(ctx context.Context, name string, shell string) (s Screen, err error) {
+// Adopt builds a Screen straight from its socket file (i.e. "/run/screen/S-user/1234.name"), without listing screens.
+// If the screen's process isn't alive, ErrNotExist type is returned.
+func Adopt(socketPath string) (s Screen, err error) {
+	return local.Adopt(socketPath)
+}
+
+// New will create a screen with the given name on the Manager's host. See New. Without a shell, the Manager's
+// DefaultShell is used.
+func (m *Manager) New(ctx context.Context, name string, shell ...string) (s Screen, err error) {
+	if name == "" {
+		if name, err = GenerateName(""); err != nil {
+			return
+		}
+	} else if err = ValidateName(name); err != nil {
+		return
+	}
+
+	// Screens of the same name are created one at a time, at least within this Manager
+	creating := m.mutex("\x00new\x00" + name)
+	creating.Lock()
+	defer creating.Unlock()
+
 	// Check for existing screen
-	if _, err = Get(name); !os.IsNotExist(err) {
-		err = &os.SyscallError{Syscall: os.ErrExist.Error(), Err: errors.New("screen already exists")}
+	if _, err = m.Get(name); err == nil {
+		err = errSessionExists()
 		return
+	} else if !IsNotFound(err) {
+		return
+	}
+
+	// With limits, screens are created one at a time, so they can't all squeeze in under them at once
+	if m.Limits.limitsSet() {
+		quota := m.mutex("\x00quota\x00")
+		quota.Lock()
+		defer quota.Unlock()
+		if err = m.checkSessionQuota(name); err != nil {
+			return
+		}
 	}
 
 	// Create new screen with name
 	var out []byte
-	out, err = exec.Command(screenExec, "-dmS", name, shell).CombinedOutput()
+	if len(shell) == 0 || shell[0] == "" {
+		shell = append([]string{m.defaultShell()}, m.DefaultShellArgs...)
+	}
+	if m.Preflight {
+		if err = m.preflight(ctx, shell[0]); err != nil {
+			return
+		}
+	}
+	params := append([]string{"-dmS", name}, m.Login.flags()...)
+	if m.WindowTitle != "" {
+		params = append(params, "-t", m.WindowTitle)
+	}
+	rcFlags, err := m.screenrcFlags()
 	if err != nil {
-		err = errors.New(string(out))
 		return
 	}
+	params = append(params, rcFlags...)
 
-	// Wait for screen to come up
-	for {
+	// The token tells our screen apart from others of the same name, created by someone else at the same time
+	token, err := newCreateToken()
+	if err != nil {
+		return
+	}
+	stdout, stderr, err := m.runEnv(ctx, []string{createTokenVar + "=" + token}, screenExec, append(params, shell...)...)
+	out = append(stdout, stderr...)
+	if err != nil {
 		if ctx.Err() != nil {
-			err = ctx.Err()
-			return
-		}
-
-		time.Sleep(time.Millisecond * 100)
-
-		s, err = Get(name)
-		if !os.IsNotExist(err) {
-			break
+			m.abandon(name, token) // It may have started before being interrupted
 		}
+		return
 	}
 
+	// Wait for screen to come up. One made by someone else at the same time was set up by them.
+	s, adopted, err := m.waitCreated(ctx, name, token, out)
+	if err != nil || adopted {
+		return
+	}
+	if err = m.setUp(s); err != nil {
+		return Screen{}, err
+	}
+	m.emit(Event{Type: EventCreated, Screen: s})
 	return
 }
 
-// Get will retrieve an existing screen, and return a Screen struct. If no screen is found, ErrNotExist type is returned.
-func Get(name string) (s Screen, err error) {
+// Get will retrieve an existing screen from the Manager's host. See Get.
+func (m *Manager) Get(name string) (s Screen, err error) {
 	if name == "" {
 		err = &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("screen name cannot be empty")}
 		return
 	}
 
-	// Run the screen -ls, check if existing screen has same name
-	out, _ := exec.Command("screen", "-ls", name).CombinedOutput() // Run screen list
-	if strings.Contains(string(out), "No Sockets found in") {
-		err = os.ErrNotExist
+	// Run the screen -ls, check if existing screen has same name. screen matches prefixes of names (and PIDs), so
+	// only exact matches count.
+	out, err := m.combined("screen", "-ls", name) // Run screen list, which exits with 1 even when it worked
+	if errors.Is(err, ErrPolicyDenied) {
 		return
 	}
+	err = nil
 
-	r, _ := regexp.Compile(fmt.Sprintf("\\s(\\d+)\\.(%s)\\s", name))
+	// Names may contain spaces or regexp metacharacters, but are always followed by a tab or the end of the line
+	r, _ := regexp.Compile(fmt.Sprintf("(?m)^\\s*(\\d+)\\.(%s)(?:\\t|$)", regexp.QuoteMeta(name)))
 	matches := r.FindAllStringSubmatch(string(out), -1)
 
 	// Check all lines
-	for _, match := range matches { // We want to skip first and last lines
-		// Parse pid and name
+	for _, match := range matches {
 		if match[2] != name {
 			continue
 		}
@@ -111,6 +170,7 @@ func Get(name string) (s Screen, err error) {
 		s.Name = name
 		if i, err := strconv.Atoi(match[1]); err == nil {
 			s.Process, _ = os.FindProcess(i)
+			s.startTime, _ = m.procStartTime(i)
 		}
 		break
 	}
@@ -120,41 +180,93 @@ func Get(name string) (s Screen, err error) {
 		return
 	}
 
-	// Load mutex from global map.
-	newM := new(sync.Mutex)
-	m, _ := mutexes.LoadOrStore(name, newM)
-	mutex, _ := m.(*sync.Mutex)
-	s.Mutex = mutex
+	s.Mutex = m.mutex(name)
+	s.manager = m
 
 	return
 }
 
-// GetAll returns all existing screens.
-func GetAll() (res []Screen) {
-	out, _ := exec.Command("screen", "-ls").CombinedOutput() // Run screen list
-	if strings.Contains(string(out), "No Sockets found in") {
-		return nil
-	}
+// GetAll returns all existing screens on the Manager's host. See GetAll.
+func (m *Manager) GetAll() (res []Screen) {
+	res, _ = m.ListSessions()
+	return
+}
+
+// ListSessions returns all existing screens on the Manager's host. See ListSessions.
+func (m *Manager) ListSessions() (res []Screen, err error) {
+	res, _, err = m.listSessions()
+	return
+}
 
-	parsed := strings.Split(string(out), "\n")
-	for i := 1; i < len(parsed)-1; i++ { // We want to skip first and last lines
-		subject := strings.Fields(parsed[i])[0]   // First part will be "<PID>.<name>"
-		nameAndPID := strings.Split(subject, ".") // Dissect
+// listSessions lists the screens on the Manager's host like ListSessions, along with what "screen -ls" says about
+// each.
+func (m *Manager) listSessions() (res []Screen, states []screenparse.SessionState, err error) {
+	out, err := m.combined("screen", "-ls") // Run screen list
+	entries := screenparse.ParseList(string(out))
+	if err = listError(out, err); errors.Is(err, ErrUnparseable) && len(entries) == 0 && m.noSessions(string(out)) {
+		return nil, nil, nil // Localized or patched builds word this differently
+	} else if err != nil && len(entries) == 0 {
+		return nil, nil, err
+	}
 
+	for _, e := range entries {
 		var s Screen
-		if i, err := strconv.Atoi(nameAndPID[0]); err == nil {
-			s.Process, _ = os.FindProcess(i)
+		var startErr error
+		s.Process, _ = os.FindProcess(e.PID)
+		s.startTime, startErr = m.procStartTime(e.PID)
+		if os.IsNotExist(startErr) && !e.Status.Dead {
+			continue // Ended since it was listed
 		}
-		s.Name = nameAndPID[1]
-
-		// Load mutex from global map.
-		m, _ := mutexes.LoadOrStore(s.Name, new(sync.Mutex))
-		mutex, _ := m.(*sync.Mutex)
-		s.Mutex = mutex
+		s.Name = e.Name
+		s.Mutex = m.mutex(s.Name)
+		s.manager = m
 
 		res = append(res, s)
+		states = append(states, e.Status.State)
+	}
+
+	return res, states, nil
+}
+
+// noSessions reports whether the Manager's host has no screens at all, without relying on the wording of
+// "screen -ls" (given as listing). "screen -ls -q" exits with 9 when there are no sockets, and if that doesn't say so,
+// an empty socket directory does.
+func (m *Manager) noSessions(listing string) bool {
+	_, _, err := m.run(context.Background(), "screen", "-ls", "-q")
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 9 {
+		return true
 	}
 
+	return m.emptySocketDir(listing)
+}
+
+// Adopt builds a Screen from a socket file on the Manager's host. See Adopt.
+func (m *Manager) Adopt(socketPath string) (s Screen, err error) {
+	// Socket files are named "<PID>.<name>", and the name may contain dots itself
+	pidAndName := strings.SplitN(path.Base(socketPath), ".", 2)
+	pid, convErr := strconv.Atoi(pidAndName[0])
+	if len(pidAndName) != 2 || convErr != nil || pid <= 0 || pidAndName[1] == "" {
+		err = &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("not a screen socket: " + socketPath)}
+		return
+	}
+
+	if err = m.stat(socketPath); err != nil {
+		return
+	}
+
+	// Signal 0 only checks that the process is there
+	if _, _, err = m.run(context.Background(), "kill", "-0", pidAndName[0]); err != nil {
+		err = os.ErrNotExist
+		return
+	}
+
+	s.Name = pidAndName[1]
+	s.Process, _ = os.FindProcess(pid)
+	s.startTime, _ = m.procStartTime(pid)
+	s.Mutex = m.mutex(s.Name)
+	s.manager = m
+
 	return
 }
 
@@ -166,13 +278,12 @@ func (s Screen) builtinTemplate(command string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	if !s.isOnline() {
-		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	if err := s.verify(); err != nil {
+		return err
 	}
 
-	out, err := exec.Command(screenExec, "-S", s.Name, "-X", command).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", command); err != nil {
+		return err
 	}
 
 	return nil
@@ -182,13 +293,12 @@ func (s Screen) builtinTemplateArgs(command string, args ...string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	if !s.isOnline() {
-		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	if err := s.verify(); err != nil {
+		return err
 	}
 
-	out, err := exec.Command(screenExec, "-S", s.Name, "-X", command, strings.Join(args, " ")).Output()
-	if err != nil {
-		return errors.New(string(out) + err.Error()) // TODO something better
+	if _, _, err := s.m().run(context.Background(), screenExec, "-S", s.target(), "-X", command, strings.Join(args, " ")); err != nil {
+		return err
 	}
 
 	return nil
@@ -209,46 +319,82 @@ func (s Screen) Stuff(commands ...string) error {
 	return s.builtinTemplateArgs("stuff", commands...)
 }
 
-// Chdir will move the screens directory. // TODO FIX
+// Source runs the screen commands in a file on the screen's host, like a screenrc.
+func (s Screen) Source(path string) error {
+	return s.builtinTemplateArgs("source", path)
+}
+
+// SourceLines runs screenrc lines in the screen, as if they were in a file given to Source. They're sent in a single
+// "eval", so there's no file to clean up afterwards.
+func (s Screen) SourceLines(lines []string) error {
+	return s.Batch(func(b *Batch) {
+		for _, line := range lines {
+			b.Line(line)
+		}
+	})
+}
+
+// SetTitle sets the title of the screen's current window.
+func (s Screen) SetTitle(title string) error {
+	return s.builtinTemplateArgs("title", title)
+}
+
+// Chdir sets the directory the screen starts new windows in, like screen's "chdir". Shells that are already running stay
+// where they are, see ChdirWindow for those. The path must be a directory on the Manager's host.
 func (s Screen) Chdir(path string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
+	if err := s.verify(); err != nil {
+		return err
+	}
+
 	// Check path
-	if _, err := os.Stat(path); err != nil {
+	if err := s.m().checkDir(path); err != nil {
 		return err
 	}
 
-	out, err := exec.Command(screenExec, "-S", s.Name, "-X", "chdir", path).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", "chdir", path); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// ChdirWindow moves the shell in the screen's current window to another directory, by typing a quoted "cd" into it, so
+// the window must be sitting at a shell prompt. Relative paths are from the shell's own directory, which isn't known, so
+// only absolute ones are checked to be a directory on the Manager's host.
+func (s Screen) ChdirWindow(path string) error {
+	if strings.HasPrefix(path, "/") {
+		if err := s.m().checkDir(path); err != nil {
+			return err
+		}
+	}
+	return s.Stuff("cd -- " + shellQuote(path) + "\n")
+}
+
 // Exec starts a new process in the same screen. Multiple processes will run independently, but share stdin, stderr, and stdout, unless specified with the fdpat.
-// fdpat is a small 1-4 character string that follows the pattern "/[.!:]{0,3}\|?$". See the "exec" section of "man screen" for more info. If you don't know, set as empty string.
-func (s Screen) Exec(fdpat string, command string, args ...string) error {
+// See FdPat, or the "exec" section of "man screen" for more info. If you don't know, use FdPat{}.
+func (s Screen) Exec(fdpat FdPat, command string, args ...string) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	if !s.isOnline() {
-		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	if err := s.verify(); err != nil {
+		return err
 	}
 
 	// Check fdpat
-	if fdpat == "" {
-	} else if match, err := regexp.MatchString("/[.!:]{0,3}\\|?$", fdpat); err != nil {
+	if err := fdpat.Validate(); err != nil {
 		return err
-	} else if !match {
-		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid fdpat")}
 	}
 
-	params := append([]string{"-S", s.Name, "-X", "exec", fdpat, command}, args...)
-	out, err := exec.Command(screenExec, params...).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	params := []string{"-S", s.target(), "-X", "exec"}
+	if pattern := fdpat.String(); pattern != "" {
+		params = append(params, pattern)
+	}
+	params = append(append(params, command), args...)
+	if _, err := s.m().combined(screenExec, params...); err != nil {
+		return err
 	}
 
 	return nil
@@ -256,56 +402,93 @@ func (s Screen) Exec(fdpat string, command string, args ...string) error {
 
 // Hardcopy copies the screen's scrollback buffer into the specified file.
 func (s Screen) Hardcopy(path string, append bool) error {
+	return s.hardcopy(path, append, false)
+}
+
+// hardcopy writes what the screen displays into a file, preceded by everything in its scrollback if scrollback is set.
+func (s Screen) hardcopy(path string, appendFile, scrollback bool) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	if !s.isOnline() {
-		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	if err := s.verify(); err != nil {
+		return err
 	}
 
 	// Set append option
 	appendString := "off"
-	if append {
+	if appendFile {
 		appendString = "on"
 	}
-	out, err := exec.Command(screenExec, "-S", s.Name, "-X", "hardcopy_append", appendString).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", "hardcopy_append", appendString); err != nil {
+		return err
 	}
 
 	// Hardcopy
-	out, err = exec.Command(screenExec, "-S", s.Name, "-X", "hardcopy", path).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	params := []string{"-S", s.target(), "-X", "hardcopy"}
+	if scrollback {
+		params = append(params, "-h")
+	}
+	if _, err := s.m().combined(screenExec, append(params, path)...); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 // Log will enable logging for a specific session. Set path to an empty string to disable logging. Default flushInterval is 10 (seconds).
+// Logging to a new path finishes the previous logfile, which is compressed and reported if the Manager asks for it (see Manager.CompressLogs).
+// Logfiles are kept under the Manager's Limits.MaxLogSize, if it has one.
 func (s Screen) Log(path string, append bool, flushInterval uint) error {
+	if err := s.m().checkLogQuota(path, append); err != nil {
+		return err
+	}
+
+	previous, _ := s.m().logs.Load(s.Name)
+	if previous != nil && previous != path && path != "" {
+		// Screen keeps writing to the old file unless logging is switched off in between
+		if err := s.builtinTemplateArgs("log", "off"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.log(path, append, flushInterval); err != nil {
+		return err
+	}
+
+	if path == "" {
+		s.m().logs.Delete(s.Name)
+	} else {
+		s.m().logs.Store(s.Name, path)
+		s.m().limitLog(s, path, flushInterval)
+	}
+	if previous != nil && previous != path {
+		return s.m().finishLog(s, previous.(string))
+	}
+	return nil
+}
+
+// log switches logging like Log, without keeping track of the logfile.
+func (s Screen) log(path string, append bool, flushInterval uint) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	if !s.isOnline() {
-		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen not found")}
+	if err := s.verify(); err != nil {
+		return err
 	}
 
 	// Logging doesn't normally append, but that's inconsistent with Hardcopy, so I'm providing the option here.
-	if _, err := os.Stat(path); err != nil && append {
-		os.Truncate(path, 0)
+	if err := s.m().stat(path); err != nil && append {
+		s.m().truncate(path)
 	} else if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
-	out, err := exec.Command(screenExec, "-S", s.Name, "-X", "logfile", path).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", "logfile", path); err != nil {
+		return err
 	}
 
-	out, err = exec.Command(screenExec, "-S", s.Name, "-X", "logfile", "flush", strconv.Itoa(int(flushInterval))).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", "logfile", "flush", strconv.Itoa(int(flushInterval))); err != nil {
+		return err
 	}
 
 	// It's worth nothing that by default, passing "", to "log" (not "logfile") toggles it, which I think isn't very useful, so "" in path means turn off.
@@ -313,14 +496,19 @@ func (s Screen) Log(path string, append bool, flushInterval uint) error {
 	if path == "" {
 		toggle = "off"
 	}
-	out, err = exec.Command(screenExec, "-S", s.Name, "-X", "log", toggle).CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	if _, err := s.m().combined(screenExec, "-S", s.target(), "-X", "log", toggle); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// SetPowDetachMessage sets the message shown to whoever gets detached with "pow_detach" (C-a D D), i.e. "Console
+// released, tell #ops", like "pow_detach_msg".
+func (s Screen) SetPowDetachMessage(msg string) error {
+	return s.builtinTemplateArgs("pow_detach_msg", msg)
+}
+
 // Clear erases the screen's scrollback buffer.
 func (s Screen) Clear() error {
 	return s.builtinTemplate("clear")
@@ -338,13 +526,19 @@ func (s Screen) Signal(signal syscall.Signal) error {
 	if !s.isOnline() {
 		return os.ErrNotExist
 	}
+	if err := s.checkProcess(); err != nil {
+		return err
+	}
+	if err := s.m().Policy.checkSignal(s.target()); err != nil {
+		return err
+	}
 
 	// Traverse PPID tree
 	var subProcs []string // PIDs for subprocesses
 	var recurse func(pid string)
 	recurse = func(pid string) {
 		// Find proc with pid as PPID, print its PID
-		out, err := exec.Command("ps", "--no-headers", "--ppid", pid, "-o", "pid:1").CombinedOutput()
+		out, err := s.m().combined("ps", "--no-headers", "--ppid", pid, "-o", "pid:1")
 		if err != nil || len(out) == 0 || len(out) == 1 {
 			return
 		}
@@ -369,27 +563,29 @@ func (s Screen) Signal(signal syscall.Signal) error {
 	// Kill all processes that pseudo terminal
 	sig := strconv.Itoa(int(signal))
 	for _, proc := range subProcs {
-		out, err := exec.Command("kill", strings.TrimSpace(proc), ("-" + sig)).CombinedOutput()
+		out, err := s.m().combined("kill", strings.TrimSpace(proc), ("-" + sig))
 		if err != nil && len(out) > 0 {
-			return errors.New(string(out))
+			return err
 		}
 	}
 
 	return nil
 }
 
-// HardcopyString copies the screen's scrollback buffer the specified file.
+// HardcopyString returns the screen's scrollback buffer. The hardcopy goes through a file in the Manager's private
+// spool directory, which is removed before returning.
 func (s Screen) HardcopyString() (string, error) {
 	// Create a temp file
-	f, err := os.CreateTemp("", "*")
+	name, err := s.m().tempFile()
 	if err != nil {
 		return "", err
 	}
-	defer os.Remove(f.Name())
-	defer f.Close()
+	defer s.m().remove(name)
 
-	s.Hardcopy(f.Name(), false)
-	b, err := os.ReadFile(f.Name())
+	if err = s.Hardcopy(name, false); err != nil {
+		return "", err
+	}
+	b, err := s.m().readFile(name)
 	if err != nil {
 		return "", err
 	}
@@ -401,53 +597,116 @@ func (s Screen) HardcopyString() (string, error) {
 // However, if you're running a program that takes certain commands into stdin (you might want to use Stuff w/ a "\n"), you have no good way of getting the output.
 // This function attempts to recreate that functionality to the best of its ability. NOTE: this function will send "\n", so you don't have to. Also, this function
 // should be used cautiously, with a long wait, then search the resulting string for your desired result.
+// Whether it returns output or ctx is done, the screen goes back to its previous logfile (see Log), and the temp file is removed.
 func (s Screen) StuffReturnGetOutput(ctx context.Context, commands ...string) (string, error) {
 	// Create a temp file
-	f, err := os.CreateTemp("", "*")
+	name, err := s.m().tempFile()
 	if err != nil {
 		return "", err
 	}
-	defer os.RemoveAll(f.Name())
-	defer f.Close()
+	defer s.m().remove(name)
 
-	err = s.Log(f.Name(), false, 1)
-	if err != nil {
+	// Log to the temp file behind the back of Log and Capture, and go back to their logfile once we're done. Screen
+	// keeps writing to the old file unless logging is switched off in between.
+	if err = s.builtinTemplateArgs("log", "off"); err != nil {
+		return "", err
+	}
+	defer s.resumeLog()
+	if err = s.log(name, false, 1); err != nil {
 		return "", err
 	}
-	time.Sleep(time.Second * 2)
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(s.m().settleDelay()):
+	}
 
 	// Run command
 	commands = append(commands, "\n")
-	s.Stuff(commands...)
+	if err = s.Stuff(commands...); err != nil {
+		return "", err
+	}
 
 	// Wait for output
-	var output string
-	waiting := make(chan (struct{}))
-	go func() {
-		for {
-			time.Sleep(time.Second)
-
-			b, err := os.ReadFile(f.Name())
-			if err != nil || len(b) == 0 {
-				continue
-			}
+	ticker := time.NewTicker(s.m().outputPoll())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
 
-			output = string(b)
-			break
+		if b, err := s.m().readFile(name); err == nil && len(b) > 0 {
+			return string(b), nil
 		}
-		waiting <- struct{}{}
-	}()
+	}
+}
 
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case <-waiting:
-		return output, nil
+// resumeLog goes back to logging where a Capture or Log wants it, after logging was switched off or went somewhere
+// else behind their back. Without either, logging stays off, and the logfile goes back to screen's default.
+func (s Screen) resumeLog() error {
+	if err := s.builtinTemplateArgs("log", "off"); err != nil {
+		return err
+	}
+	if fifo, ok := s.m().captures.Load(s.Name); ok {
+		return s.log(fifo.(string), true, 1)
+	}
+	if path, ok := s.m().logs.Load(s.Name); ok {
+		return s.log(path.(string), true, 10)
 	}
+	return s.builtinTemplateArgs("logfile", "screenlog.%n")
 }
 
 // isOnline is a quick helper function to check if a screen is still currently running.
 func (s Screen) isOnline() bool {
-	s, err := Get(s.Name)
-	return err == nil
+	current, err := s.m().Get(s.Name)
+	if err != nil {
+		return false
+	}
+
+	// A screen recreated under the same name is a different screen
+	return s.Process == nil || current.Process == nil || current.Process.Pid == s.Process.Pid
+}
+
+// checkProcess makes sure the screen's PID still belongs to the screen, and not to some process that got the PID after
+// the screen ended, so we never signal an unrelated process.
+func (s Screen) checkProcess() error {
+	if s.Process == nil {
+		return os.ErrNotExist
+	}
+	if s.startTime == 0 {
+		return nil // Couldn't tell when we looked it up, so there's nothing to compare against
+	}
+
+	startTime, err := s.m().procStartTime(s.Process.Pid)
+	if err != nil || startTime != s.startTime {
+		return &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("screen process was replaced")}
+	}
+	return nil
+}
+
+// target returns how to address the screen with -S. "<PID>.<name>" is exact, a bare name is matched as a prefix.
+func (s Screen) target() string {
+	if s.Process == nil {
+		return s.Name
+	}
+	return strconv.Itoa(s.Process.Pid) + "." + s.Name
+}
+
+// m returns the Manager the screen belongs to.
+func (s Screen) m() *Manager {
+	if s.manager == nil {
+		return local
+	}
+	return s.manager
+}
+
+// onOff formats a bool the way screen commands take it.
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
 }
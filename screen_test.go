@@ -3,6 +3,8 @@ package screen
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -47,3 +49,75 @@ func TestStuffReturnGetOutput(t *testing.T) {
 	}
 	t.Log(str)
 }
+
+func TestAdopt(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), fmt.Sprintf("%d.my.session", os.Getpid()))
+	if err := os.WriteFile(socket, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Adopt(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "my.session" || s.Process.Pid != os.Getpid() {
+		t.Errorf("got %q with PID %d", s.Name, s.Process.Pid)
+	}
+
+	if _, err = Adopt(filepath.Join(t.TempDir(), "banana")); err == nil {
+		t.Error("expected an error for a path that isn't a socket")
+	}
+}
+
+func TestCheckProcess(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), fmt.Sprintf("%d.self", os.Getpid()))
+	if err := os.WriteFile(socket, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Adopt(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.checkProcess(); err != nil {
+		t.Errorf("got %v for the original process", err)
+	}
+
+	s.startTime++ // As if the PID now belonged to a newer process
+	if err = s.checkProcess(); err == nil {
+		t.Error("expected an error for a reused PID")
+	}
+}
+
+func TestChdir(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":     fakeList,
+		"test -e /srv/it's":  "",
+		"test -d /srv/it's":  "",
+		"test -e /srv/notes": "",
+		"test -e /srv/gone":  "",
+		"/usr/bin/screen -S 4242.a+b -X chdir /srv/it's":               "",
+		"/usr/bin/screen -S 4242.a+b -X stuff cd -- '/srv/it'\\''s'\n": "",
+		"/usr/bin/screen -S 4242.a+b -X stuff cd -- 'logs'\n":          "",
+	}, exits: map[string]int{"test -d /srv/notes": 1, "test -e /srv/gone": 1}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Chdir("/srv/it's"); err != nil {
+		t.Error(err)
+	}
+	if err = s.ChdirWindow("/srv/it's"); err != nil {
+		t.Error(err)
+	}
+	if err = s.ChdirWindow("logs"); err != nil {
+		t.Error(err)
+	}
+	if err = s.Chdir("/srv/notes"); err == nil || IsNotFound(err) {
+		t.Errorf("got %v, want an error for a file", err)
+	}
+	if err = s.ChdirWindow("/srv/gone"); !IsNotFound(err) {
+		t.Errorf("got %v, want not found", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package screen
+
+import (
+	"os"
+	"os/user"
+)
+
+// init will get called automatically when the library is used
+func init() {
+	// Get user, the socket directory is named after them
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	} else {
+		username = os.Getenv("USER")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package screen
+
+// init will get called automatically when the library is used. Windows has no screen of its own, so the package-level
+// functions go through the default WSL distribution instead.
+func init() {
+	local = NewWSLManager("")
+}
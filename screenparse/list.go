@@ -0,0 +1,153 @@
+// Package screenparse parses the output of GNU screen's "-ls", "-Q" and "-v" commands. It's what the screen package
+// uses internally, exposed for tools that want to read screen's output themselves.
+package screenparse
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnparseable is returned when screen's output doesn't look like anything we know.
+var ErrUnparseable = errors.New("unrecognized screen output")
+
+// SessionState is whether anyone is attached to a screen, as reported by "screen -ls".
+type SessionState int
+
+const (
+	StateUnknown SessionState = iota
+	StateAttached
+	StateDetached
+	StateDead
+)
+
+// String returns the state the way screen prints it.
+func (st SessionState) String() string {
+	switch st {
+	case StateAttached:
+		return "Attached"
+	case StateDetached:
+		return "Detached"
+	case StateDead:
+		return "Dead"
+	}
+	return "Unknown"
+}
+
+// Status is everything "screen -ls" knows about a screen.
+type Status struct {
+	State SessionState
+	// Attached is the number of attached displays. "screen -ls" only says whether there are any, so it's 0 or 1.
+	Attached  int
+	Multiuser bool
+	Dead      bool
+	// Unreachable is set when screen couldn't connect to the session's socket, i.e. it belongs to another user.
+	Unreachable bool
+	CreatedAt   time.Time // Zero if screen didn't print it, or printed it in a layout we don't know
+
+	// The socket isn't part of a "screen -ls" line, these are only filled in by the screen package.
+	SocketPath string      // Empty if screen didn't say where its sockets are
+	SocketMode os.FileMode // Permissions of the socket, which screen also uses to mark attached (u+x) and multiuser (g+x) sessions
+}
+
+// ListEntry is a parsed session line from "screen -ls".
+type ListEntry struct {
+	PID    int
+	Name   string
+	Status Status
+}
+
+// Layouts screen prints creation times in. It uses the locale's date and time, so this is best effort.
+var createdLayouts = []string{
+	"01/02/2006 03:04:05 PM",
+	"01/02/2006 15:04:05",
+	"01/02/06 15:04:05",
+	"01/02/06 03:04:05 PM",
+	"2006-01-02 15:04:05",
+	"02.01.2006 15:04:05",
+}
+
+// ParseListLine parses a session line of "screen -ls", i.e. "\t1234.name\t(10/15/2026 10:00:00 AM)\t(Detached)".
+// ok is false for anything else, like the header and footer lines.
+func ParseListLine(line string) (e ListEntry, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	// The session is everything up to the first parenthesized field, names may contain spaces
+	session := line
+	var fields []string
+	if i := strings.Index(line, "\t("); i >= 0 {
+		session = strings.TrimSpace(line[:i])
+		for _, f := range strings.Split(line[i:], "\t") {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "(") && strings.HasSuffix(f, ")") {
+				fields = append(fields, f[1:len(f)-1])
+			}
+		}
+	}
+
+	pidAndName := strings.SplitN(session, ".", 2)
+	if len(pidAndName) != 2 || pidAndName[1] == "" {
+		return
+	}
+	pid, err := strconv.Atoi(pidAndName[0])
+	if err != nil || pid <= 0 {
+		return
+	}
+	e.PID, e.Name = pid, pidAndName[1]
+
+	for _, f := range fields {
+		lower := strings.ToLower(f)
+		switch {
+		case strings.HasPrefix(lower, "dead"), strings.Contains(lower, "remote or dead"):
+			e.Status.State, e.Status.Dead = StateDead, true
+		case strings.HasPrefix(lower, "unreachable"):
+			e.Status.Unreachable = true
+		case strings.Contains(lower, "attached"):
+			e.Status.State, e.Status.Attached = StateAttached, 1
+			e.Status.Multiuser = strings.HasPrefix(lower, "multi")
+		case strings.Contains(lower, "detached"):
+			e.Status.State = StateDetached
+			e.Status.Multiuser = strings.HasPrefix(lower, "multi")
+		default:
+			for _, layout := range createdLayouts {
+				if t, err := time.ParseInLocation(layout, f, time.Local); err == nil {
+					e.Status.CreatedAt = t
+					break
+				}
+			}
+		}
+	}
+
+	return e, true
+}
+
+// ParseList parses every session line of "screen -ls". Header, footer and hint lines, which differ between versions
+// of screen, are skipped, and so is anything else that doesn't look like a session. A PID listed more than once only
+// counts the first time. The session lines of every version we know (see testdata/ls) parse the same way, so it
+// doesn't need to know which version printed them.
+func ParseList(out string) (res []ListEntry) {
+	seen := map[int]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		if e, ok := ParseListLine(line); ok && !seen[e.PID] {
+			seen[e.PID] = true
+			res = append(res, e)
+		}
+	}
+	return
+}
+
+var socketDirRegexp = regexp.MustCompile(`(?m)Sockets? (?:found )?in (.+?)\.?$`)
+
+// ParseSocketDir returns the socket directory named in the last line of "screen -ls", or "" if there's none.
+func ParseSocketDir(out string) string {
+	if match := socketDirRegexp.FindStringSubmatch(out); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
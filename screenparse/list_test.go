@@ -0,0 +1,135 @@
+package screenparse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseListLine(t *testing.T) {
+	tests := []struct {
+		line string
+		ok   bool
+		want ListEntry
+	}{
+		{"There are screens on:", false, ListEntry{}},
+		{"2 Sockets in /run/screen/S-root.", false, ListEntry{}},
+		{"1 Socket in /run/screen/S-john.doe.", false, ListEntry{}},
+		{"Remove dead screens with 'screen -wipe'.", false, ListEntry{}},
+		{"\t", false, ListEntry{}},
+		{"\t.\t(Detached)", false, ListEntry{}},
+		{"\t42.\t(Detached)", false, ListEntry{}},
+		{"\t-1.negative\t(Detached)", false, ListEntry{}},
+		{"\t(Detached)", false, ListEntry{}},
+		{"\t4242.banana\t(Detached)", true, ListEntry{PID: 4242, Name: "banana", Status: Status{State: StateDetached}}},
+		{"\t17.deploy europe-west 1\t(Attached)", true, ListEntry{PID: 17, Name: "deploy europe-west 1", Status: Status{State: StateAttached, Attached: 1}}},
+		{"\t99.a.b\t(Multi, attached)", true, ListEntry{PID: 99, Name: "a.b", Status: Status{State: StateAttached, Attached: 1, Multiuser: true}}},
+		{"\t5.gone\t(Dead ???)", true, ListEntry{PID: 5, Name: "gone", Status: Status{State: StateDead, Dead: true}}},
+		{"\t6.theirs\t(Multi, detached)\t(Unreachable)", true, ListEntry{PID: 6, Name: "theirs", Status: Status{State: StateDetached, Multiuser: true, Unreachable: true}}},
+		{
+			"\t8.dated\t(10/15/2026 09:30:00 AM)\t(Detached)", true,
+			ListEntry{PID: 8, Name: "dated", Status: Status{State: StateDetached, CreatedAt: time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local)}},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := ParseListLine(test.line)
+		if ok != test.ok || got.PID != test.want.PID || got.Name != test.want.Name || got.Status != test.want.Status {
+			t.Errorf("ParseListLine(%q) = %+v, %v, want %+v, %v", test.line, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestParseSocketDir(t *testing.T) {
+	tests := map[string]string{
+		"There is a screen on:\n\t1.a\t(Detached)\n1 Socket in /run/screen/S-root.\n": "/run/screen/S-root",
+		"No Sockets found in /tmp/screens/S-bob.\n":                                   "/tmp/screens/S-bob",
+		"garbage": "",
+	}
+
+	for out, want := range tests {
+		if got := ParseSocketDir(out); got != want {
+			t.Errorf("ParseSocketDir(%q) = %q, want %q", out, got, want)
+		}
+	}
+}
+
+// TestListFixtures parses "screen -ls" output captured from different versions of screen, see testdata/ls.
+func TestListFixtures(t *testing.T) {
+	// Fixtures with creation times have the sessions made at 2026-10-15 09:30 and 09:31, in the local time zone
+	both := [2]time.Time{
+		time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local),
+		time.Date(2026, 10, 15, 9, 31, 0, 0, time.Local),
+	}
+	tests := map[string]struct {
+		created [2]time.Time // Of banana and deploy europe-west 1
+		dead    bool         // Whether 4300.old is listed as dead after them
+	}{
+		"4.00.03.txt": {},
+		"4.06.02.txt": {created: both},
+		"4.09.00.txt": {created: both, dead: true},
+		"5.0.0.txt":   {created: both},
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "ls", "*.txt"))
+	if err != nil || len(fixtures) != len(tests) {
+		t.Fatalf("got fixtures %q, %v, want %d", fixtures, err, len(tests))
+	}
+
+	for _, fixture := range fixtures {
+		want, ok := tests[filepath.Base(fixture)]
+		if !ok {
+			t.Fatalf("%s: no expectations", fixture)
+		}
+		b, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if dir := ParseSocketDir(string(b)); dir == "" {
+			t.Errorf("%s: no socket directory", fixture)
+		}
+
+		entries := ParseList(string(b))
+		wantLen := 2
+		if want.dead {
+			wantLen = 3
+		}
+		if len(entries) != wantLen {
+			t.Fatalf("%s: got %d sessions, want %d", fixture, len(entries), wantLen)
+		}
+		if e := entries[0]; e.PID != 4242 || e.Name != "banana" || e.Status.State != StateDetached {
+			t.Errorf("%s: got %+v", fixture, e)
+		}
+		if e := entries[1]; e.PID != 4250 || e.Name != "deploy europe-west 1" || e.Status.State != StateAttached {
+			t.Errorf("%s: got %+v", fixture, e)
+		}
+		for i, at := range want.created {
+			if got := entries[i].Status.CreatedAt; !got.Equal(at) {
+				t.Errorf("%s: %s was created at %v, want %v", fixture, entries[i].Name, got, at)
+			}
+		}
+		if want.dead {
+			if e := entries[2]; e.PID != 4300 || e.Name != "old" || e.Status.State != StateDead || !e.Status.Dead {
+				t.Errorf("%s: got %+v, want a dead session", fixture, e)
+			}
+		}
+	}
+}
+
+func TestParseListChurn(t *testing.T) {
+	out := "There are screens on:\n" +
+		"\t4242.banana\t(Detached)\n" +
+		"\n" +
+		"\t4242.banana\t(Detached)\n" + // Listed twice while its socket was being replaced
+		"\t???\n" +
+		"\t5.gone\t(Dead ???)\n" +
+		"Remove dead screens with 'screen -wipe'.\n" +
+		"2 Sockets in /run/screen/S-root.\n"
+
+	got := ParseList(out)
+	if len(got) != 2 || got[0].PID != 4242 || got[1].PID != 5 {
+		t.Errorf("got %+v", got)
+	}
+}
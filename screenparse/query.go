@@ -0,0 +1,52 @@
+package screenparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QueryResult is the reply of "screen -Q" to a command that reports on a window, like "number" or "select",
+// which answer with the window's number and title, i.e. "0 (bash)".
+type QueryResult struct {
+	Number int    // -1 if the reply didn't start with a window number
+	Title  string // Empty if the reply had no parenthesized title
+	Raw    string // The whole reply, without the trailing newline
+}
+
+var queryRegexp = regexp.MustCompile(`^(\d+)(?:\s+\((.*)\))?`)
+
+// ParseQuery parses the reply of "screen -Q" to a window command.
+func ParseQuery(out string) QueryResult {
+	res := QueryResult{Number: -1, Raw: strings.TrimRight(out, "\r\n")}
+	if match := queryRegexp.FindStringSubmatch(strings.TrimSpace(out)); match != nil {
+		res.Number, _ = strconv.Atoi(match[1])
+		res.Title = match[2]
+	}
+	return res
+}
+
+// WindowEntry is a window in the reply of "screen -Q windows".
+type WindowEntry struct {
+	Number int
+	Flags  string // Screen's window flags, i.e. "*" for the current window, "-" for the previous one, "$" for a login
+	Title  string
+}
+
+var windowRegexp = regexp.MustCompile(`^(\d+)([^\d\s]*)\s(.*)$`)
+
+// ParseWindows parses the reply of "screen -Q windows", i.e. "0$ bash  1*$ vim". Windows are separated by two
+// spaces, so titles containing two spaces in a row can't be told apart; entries that don't start with a window
+// number are added to the title before them.
+func ParseWindows(out string) []WindowEntry {
+	var res []WindowEntry
+	for _, part := range strings.Split(strings.TrimRight(out, "\r\n"), "  ") {
+		if match := windowRegexp.FindStringSubmatch(part); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			res = append(res, WindowEntry{Number: number, Flags: match[2], Title: match[3]})
+		} else if len(res) > 0 {
+			res[len(res)-1].Title += "  " + part
+		}
+	}
+	return res
+}
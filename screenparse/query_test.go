@@ -0,0 +1,39 @@
+package screenparse
+
+import "testing"
+
+func TestParseQuery(t *testing.T) {
+	tests := map[string]QueryResult{
+		"0 (bash)\n":        {Number: 0, Title: "bash", Raw: "0 (bash)"},
+		"12 (make (all))\n": {Number: 12, Title: "make (all)", Raw: "12 (make (all))"},
+		"3\n":               {Number: 3, Raw: "3"},
+		"No window.\n":      {Number: -1, Raw: "No window."},
+	}
+
+	for out, want := range tests {
+		if got := ParseQuery(out); got != want {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", out, got, want)
+		}
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	got := ParseWindows("0$ bash  1-$ make  all  12*$ vim main.go\n")
+	want := []WindowEntry{
+		{Number: 0, Flags: "$", Title: "bash"},
+		{Number: 1, Flags: "-$", Title: "make  all"},
+		{Number: 12, Flags: "*$", Title: "vim main.go"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %+v, want %+v", got[i], want[i])
+		}
+	}
+
+	if got := ParseWindows("\n"); len(got) != 0 {
+		t.Errorf("got %+v for no windows", got)
+	}
+}
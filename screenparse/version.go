@@ -0,0 +1,32 @@
+package screenparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Version is a version of screen, i.e. 4.9.0 for "Screen version 4.09.00".
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// String formats the version like "4.9.0".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+var versionRegexp = regexp.MustCompile(`Screen version (\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion parses the output of "screen -v".
+func ParseVersion(out string) (v Version, err error) {
+	match := versionRegexp.FindStringSubmatch(out)
+	if match == nil {
+		return v, fmt.Errorf("%w: %q", ErrUnparseable, out)
+	}
+
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	v.Patch, _ = strconv.Atoi(match[3]) // Optional, 0 if missing
+	return v, nil
+}
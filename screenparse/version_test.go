@@ -0,0 +1,24 @@
+package screenparse
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"Screen version 4.00.03 (FAU) 23-Oct-06\n": {4, 0, 3},
+		"Screen version 4.09.00 (GNU) 30-Jan-22\n": {4, 9, 0},
+		"Screen version 5.0.0 (GNU) 28-Aug-24\n":   {5, 0, 0},
+	}
+
+	for out, want := range tests {
+		if got, err := ParseVersion(out); err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v, want %v", out, got, err, want)
+		}
+	}
+
+	if _, err := ParseVersion("screen: command not found"); !errors.Is(err, ErrUnparseable) {
+		t.Errorf("expected ErrUnparseable for unrelated output, got %v", err)
+	}
+}
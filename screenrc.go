@@ -0,0 +1,76 @@
+package screen
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Screenrc is a screenrc the Manager generates and starts every screen New makes with ("-c"), so screens get the
+// same defaults no matter what the host user's ~/.screenrc says. The system-wide /etc/screenrc is still read first.
+// Zero fields are left out, which leaves screen's own default.
+type Screenrc struct {
+	Scrollback    int      // Lines of scrollback of every window, "defscrollback"
+	Shell         string   // Of new windows, "shell"; New's shell still wins for the first window
+	Term          string   // $TERM inside windows, "term", i.e. "screen-256color"
+	Escape        string   // Command key, "escape", i.e. "^Bb"
+	Encoding      string   // Of new windows, "defencoding", i.e. "UTF-8"
+	UTF8          bool     // New windows are UTF-8, "defutf8 on"
+	AltScreen     bool     // Full screen programs get the alternate screen, "altscreen on"
+	NoFlowControl bool     // ^S and ^Q reach the application, "defflow off"
+	Lines         []string // Added verbatim after everything else
+}
+
+// String returns the screenrc.
+func (rc Screenrc) String() string {
+	var b Batch
+	if rc.Scrollback > 0 {
+		b.Command("defscrollback", strconv.Itoa(rc.Scrollback))
+	}
+	if rc.Shell != "" {
+		b.Command("shell", rc.Shell)
+	}
+	if rc.Term != "" {
+		b.Command("term", rc.Term)
+	}
+	if rc.Escape != "" {
+		b.Command("escape", rc.Escape)
+	}
+	if rc.Encoding != "" {
+		b.Command("defencoding", rc.Encoding)
+	}
+	if rc.UTF8 {
+		b.Command("defutf8", "on")
+	}
+	if rc.AltScreen {
+		b.Command("altscreen", "on")
+	}
+	if rc.NoFlowControl {
+		b.Command("defflow", "off")
+	}
+	for _, line := range rc.Lines {
+		b.Line(line)
+	}
+
+	if len(b.commands) == 0 {
+		return ""
+	}
+	return strings.Join(b.commands, "\n") + "\n"
+}
+
+// screenrcFlags writes the Manager's Screenrc into its spool directory, and returns the flags for New to use it.
+func (m *Manager) screenrcFlags() ([]string, error) {
+	if m.Screenrc == nil {
+		return nil, nil
+	}
+
+	dir, err := m.spoolDir()
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(dir, "screenrc")
+	if err = m.writeFile(name, []byte(m.Screenrc.String())); err != nil {
+		return nil, err
+	}
+	return []string{"-c", name}, nil
+}
@@ -0,0 +1,39 @@
+package screen
+
+import (
+	"os"
+	"testing"
+)
+
+func TestScreenrcString(t *testing.T) {
+	rc := Screenrc{Scrollback: 5000, Term: "screen-256color", UTF8: true, NoFlowControl: true, Lines: []string{"# comment", "vbell off"}}
+	want := "defscrollback '5000'\nterm 'screen-256color'\ndefutf8 'on'\ndefflow 'off'\nvbell off\n"
+	if got := rc.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (Screenrc{}).String(); got != "" {
+		t.Errorf("got %q for an empty screenrc", got)
+	}
+}
+
+func TestScreenrcFlags(t *testing.T) {
+	m := NewManager()
+	defer m.Close()
+
+	if flags, err := m.screenrcFlags(); err != nil || flags != nil {
+		t.Fatalf("got %q, %v without a Screenrc", flags, err)
+	}
+
+	m.Screenrc = &Screenrc{Scrollback: 100}
+	flags, err := m.screenrcFlags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flags) != 2 || flags[0] != "-c" {
+		t.Fatalf("got %q", flags)
+	}
+	b, err := os.ReadFile(flags[1])
+	if err != nil || string(b) != "defscrollback '100'\n" {
+		t.Errorf("got %q, %v", b, err)
+	}
+}
@@ -0,0 +1,53 @@
+// Package screentest provides helpers for tests that need a real screen.
+package screentest
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// New creates a screen with a unique name for the duration of the test, running shell (see screen.New). The screen
+// is killed and wiped when the test ends. If screen isn't installed, or can't be used on this machine, the test is
+// skipped instead.
+func New(t testing.TB, shell ...string) screen.Screen {
+	t.Helper()
+
+	if _, err := exec.LookPath("screen"); err != nil {
+		t.Skip("screen is not installed")
+	}
+	if _, err := screen.ListSessions(); errors.Is(err, screen.ErrNotInstalled) || errors.Is(err, screen.ErrSocketDirPermission) {
+		t.Skip("screen can't be used:", err)
+	}
+
+	prefix := "screentest-" + strings.Trim(unsafeChars.ReplaceAllString(t.Name(), "-"), "-")
+	if len(prefix) > 40 {
+		prefix = prefix[:40] // Leave room for GenerateName's suffix
+	}
+	name, err := screen.GenerateName(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	s, err := screen.New(ctx, name, shell...)
+	if err != nil {
+		t.Fatalf("creating screen %q: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		s.Quit()
+		exec.Command("screen", "-wipe", name).Run() // Clear the socket if the screen died instead
+	})
+
+	return s
+}
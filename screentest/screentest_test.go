@@ -0,0 +1,17 @@
+package screentest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	s := New(t, "sh")
+
+	if !strings.HasPrefix(s.Name, "screentest-TestNew-") {
+		t.Errorf("got name %q", s.Name)
+	}
+	if err := s.Stuff("echo hello\n"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,82 @@
+package screenweb
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Scope is what a token allows. Scopes combine with |.
+type Scope int
+
+const (
+	ScopeRead  Scope = 1 << iota // List screens and watch their terminals
+	ScopeStuff                   // Type into screens
+	ScopeKill                    // Kill screens
+
+	ScopeAll = ScopeRead | ScopeStuff | ScopeKill
+)
+
+// Tokens maps API tokens to what they allow.
+type Tokens map[string]Scope
+
+type scopeKey struct{}
+
+// Middleware only lets requests through that carry one of the tokens, as "Authorization: Bearer <token>", as
+// "X-API-Key: <token>", or as a "token" query parameter (browsers can't set headers on WebSockets). The token's scope
+// is passed on to the Handler, which refuses what it doesn't allow.
+func (t Tokens) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		scope, ok := t.lookup(token)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="screenweb"`)
+			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+		sum := sha256.Sum256([]byte(token)) // Clients are told apart by token, without keeping it around
+		next.ServeHTTP(w, r.WithContext(withClient(r.Context(), scope, "token:"+hex.EncodeToString(sum[:8]))))
+	})
+}
+
+// withClient records what an authenticated client may do, and who it is (see Limiter).
+func withClient(ctx context.Context, scope Scope, client string) context.Context {
+	return context.WithValue(context.WithValue(ctx, scopeKey{}, scope), clientKey{}, client)
+}
+
+// lookup finds a token's scope. Every token is compared in constant time, so timing doesn't give away valid ones.
+func (t Tokens) lookup(token string) (scope Scope, ok bool) {
+	if token == "" {
+		return 0, false
+	}
+	for known, s := range t {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			scope, ok = s, true
+		}
+	}
+	return
+}
+
+// requestToken returns the token a request carries, or "".
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return r.URL.Query().Get("token")
+}
+
+// allowed reports whether a request may do what scope covers. Requests that didn't go through Middleware (or
+// CommonNames.Middleware) may do nothing, unless the Handler is Unauthenticated.
+func (h *Handler) allowed(r *http.Request, scope Scope) bool {
+	granted, ok := r.Context().Value(scopeKey{}).(Scope)
+	if !ok {
+		return h.Unauthenticated
+	}
+	return granted&scope == scope
+}
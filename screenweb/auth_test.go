@@ -0,0 +1,41 @@
+package screenweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+func TestTokensMiddleware(t *testing.T) {
+	h := Tokens{"reader": ScopeRead, "admin": ScopeAll}.Middleware(
+		NewHandler(screen.NewManagerWithRunner(fakeRunner("\t42.build\t(Detached)\n"))))
+
+	tests := []struct {
+		method, path string
+		header       map[string]string
+		want         int
+	}{
+		{http.MethodGet, "/api/sessions", nil, http.StatusUnauthorized},
+		{http.MethodGet, "/api/sessions", map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized},
+		{http.MethodGet, "/api/sessions", map[string]string{"Authorization": "Bearer reader"}, http.StatusOK},
+		{http.MethodGet, "/api/sessions", map[string]string{"X-API-Key": "admin"}, http.StatusOK},
+		{http.MethodGet, "/api/sessions?token=reader", nil, http.StatusOK},
+		{http.MethodPost, "/api/kill?name=build", map[string]string{"Authorization": "Bearer reader"}, http.StatusForbidden},
+		{http.MethodPost, "/api/stuff?name=build", map[string]string{"X-API-Key": "reader"}, http.StatusForbidden},
+		{http.MethodPost, "/api/kill?name=nope", map[string]string{"X-API-Key": "admin"}, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		for k, v := range test.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.want {
+			t.Errorf("%s %s %v: got %d, want %d", test.method, test.path, test.header, rec.Code, test.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package screenweb serves a small web dashboard for screens: a list of sessions, and a live terminal for each one
+// (xterm.js, bridged to the screen over a WebSocket), so long-running jobs can be watched from a browser.
+//
+//	tokens := screenweb.Tokens{os.Getenv("SCREENWEB_TOKEN"): screenweb.ScopeAll}
+//	http.Handle("/screens/", http.StripPrefix("/screens", tokens.Middleware(screenweb.NewHandler(nil))))
+//
+// Whoever holds a token can see and type into every screen of the Manager its scope covers. Without Tokens.Middleware
+// or CommonNames.Middleware in front of it, the handler refuses every API request, unless it's told that other
+// authentication is in front of it with Handler.Unauthenticated.
+package screenweb
+
+import (
+	"context"
+	_ "embed" // For index.html
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Handler serves the dashboard:
+//
+//	GET  /                          the web UI
+//	GET  /api/sessions              the screens, as JSON (ScopeRead)
+//	GET  /api/terminal?name=<name>  a WebSocket bridged to a terminal attached to the screen (ScopeRead, and ScopeStuff
+//	                                to type into it)
+//	POST /api/stuff?name=<name>     type the request body into the screen (ScopeStuff)
+//	POST /api/kill?name=<name>      kill the screen (ScopeKill)
+//
+// Terminal output is sent as binary messages. The browser sends text messages, {"type": "input", "data": "..."} to
+// type and {"type": "resize", "cols": 80, "rows": 24} when its terminal changes size.
+type Handler struct {
+	// ReadOnly attaches terminals with Observe, so browsers can watch but not type.
+	ReadOnly bool
+	// Unauthenticated lets requests that didn't go through Tokens.Middleware or CommonNames.Middleware do anything.
+	// Without it, they're refused, so a Handler mounted without authentication can't hand out control of the screens
+	// by accident. Only set it if something else authenticates requests.
+	Unauthenticated bool
+
+	m   *screen.Manager
+	mux *http.ServeMux
+}
+
+// NewHandler returns a Handler for the screens of m. A nil m manages the local machine.
+func NewHandler(m *screen.Manager) *Handler {
+	if m == nil {
+		m = screen.NewManager()
+	}
+
+	h := &Handler{m: m, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/", h.index)
+	h.mux.HandleFunc("/api/sessions", h.sessions)
+	h.mux.HandleFunc("/api/terminal", h.terminal)
+	h.mux.HandleFunc("/api/stuff", h.stuff)
+	h.mux.HandleFunc("/api/kill", h.kill)
+	return h
+}
+
+// ServeHTTP serves the dashboard.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
+
+func (h *Handler) sessions(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(r, ScopeRead) {
+		http.Error(w, "token doesn't allow reading", http.StatusForbidden)
+		return
+	}
+
+	screens, err := h.m.ListSessions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if screens == nil {
+		screens = []screen.Screen{} // [] rather than null
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(screens)
+}
+
+// terminalMessage is a message from the browser's terminal.
+type terminalMessage struct {
+	Type string `json:"type"` // "input" or "resize"
+	Data string `json:"data,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+func (h *Handler) terminal(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(r, ScopeRead) {
+		http.Error(w, "token doesn't allow reading", http.StatusForbidden)
+		return
+	}
+	s, ok := h.get(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// The request's context ends with the handler, which is fine since the handler waits for the terminal
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var a *screen.Attachment
+	if h.ReadOnly || !h.allowed(r, ScopeStuff) {
+		a, err = s.Observe(ctx)
+	} else {
+		a, err = s.AttachShared(ctx)
+	}
+	if err != nil {
+		conn.WriteMessage(opBinary, []byte("\r\n"+err.Error()+"\r\n"))
+		return
+	}
+	defer a.Close()
+
+	// Terminal to browser, until the screen detaches
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := a.Read(buf)
+			if n > 0 && conn.WriteMessage(opBinary, buf[:n]) != nil {
+				break
+			}
+			if err != nil {
+				break
+			}
+		}
+		conn.conn.Close() // Unblocks ReadMessage below
+	}()
+
+	// Browser to terminal, until the browser goes away
+	for {
+		op, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if op != opText {
+			continue
+		}
+
+		var m terminalMessage
+		if json.Unmarshal(msg, &m) != nil {
+			continue
+		}
+		switch m.Type {
+		case "input":
+			a.Write([]byte(m.Data)) // Fails with ErrReadOnly when observing, which is what we want
+		case "resize":
+			if m.Cols > 0 && m.Rows > 0 {
+				a.Resize(m.Cols, m.Rows)
+			}
+		}
+	}
+}
+
+func (h *Handler) stuff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.ReadOnly || !h.allowed(r, ScopeStuff) {
+		http.Error(w, "token doesn't allow stuffing", http.StatusForbidden)
+		return
+	}
+	s, ok := h.get(w, r)
+	if !ok {
+		return
+	}
+
+	text, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = s.Stuff(string(text)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) kill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.ReadOnly || !h.allowed(r, ScopeKill) {
+		http.Error(w, "token doesn't allow killing", http.StatusForbidden)
+		return
+	}
+	s, ok := h.get(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.Kill(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// get looks up the screen named by the request's "name" parameter. If it can't, it writes an error response.
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) (screen.Screen, bool) {
+	s, err := h.m.Get(r.URL.Query().Get("name"))
+	if os.IsNotExist(err) {
+		http.NotFound(w, r)
+		return s, false
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return s, false
+	}
+	return s, true
+}
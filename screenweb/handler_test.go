@@ -0,0 +1,61 @@
+package screenweb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+// fakeRunner answers "screen -ls" with a fixed listing, and fails everything else.
+type fakeRunner string
+
+func (r fakeRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "screen" && len(args) == 1 && args[0] == "-ls" {
+		return []byte(r), nil, nil
+	}
+	return nil, []byte("unexpected command"), &exec.ExitError{}
+}
+
+func TestHandlerSessions(t *testing.T) {
+	h := NewHandler(screen.NewManagerWithRunner(fakeRunner("\t42.build\t(Detached)\n\t43.deploy\t(Attached)\n")))
+	h.Unauthenticated = true
+
+	for path, want := range map[string]string{
+		"/api/sessions": `[{"name":"build","pid":42},{"name":"deploy","pid":43}]` + "\n",
+		"/":             "<!DOCTYPE html>",
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), want) {
+			t.Errorf("%s: got %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/terminal?name=nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got %d for a missing screen, want 404", rec.Code)
+	}
+}
+
+func TestHandlerUnauthenticated(t *testing.T) {
+	h := NewHandler(screen.NewManagerWithRunner(fakeRunner("\t42.build\t(Detached)\n")))
+
+	// Mounted without authentication, nothing but the page itself is served
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/sessions", nil),
+		httptest.NewRequest(http.MethodPost, "/api/stuff?name=build", strings.NewReader("rm -rf /\n")),
+		httptest.NewRequest(http.MethodPost, "/api/kill?name=build", nil),
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: got %d, want 403", req.URL, rec.Code)
+		}
+	}
+}
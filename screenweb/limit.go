@@ -0,0 +1,144 @@
+package screenweb
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter protects the host from a misbehaving client: every request to the Handler runs screen commands, and every
+// terminal a screen client. Put it inside Tokens.Middleware or CommonNames.Middleware, so clients are told apart by
+// their token or certificate rather than their address:
+//
+//	tokens.Middleware(limiter.Middleware(handler))
+type Limiter struct {
+	RequestsPerSecond float64 // Per client, unlimited if zero
+	Burst             int     // Requests a client can make at once before RequestsPerSecond kicks in, at least 1
+	MaxInFlight       int     // Requests being handled at once across all clients, unlimited if zero; terminals don't count
+	MaxTerminals      int     // Terminals open at once across all clients, unlimited if zero
+
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	inFlight  int
+	terminals int
+}
+
+// bucket is a token bucket of a client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type clientKey struct{}
+
+// Middleware refuses requests over the limits with 429 Too Many Requests or 503 Service Unavailable.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(client(r), time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		terminal := headerContains(r.Header, "Upgrade", "websocket")
+		if !l.acquire(terminal) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "server busy", http.StatusServiceUnavailable)
+			return
+		}
+		defer l.release(terminal)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// client identifies who sent a request: the token or certificate the auth middleware found, or the address.
+func client(r *http.Request) string {
+	if key, ok := r.Context().Value(clientKey{}).(string); ok {
+		return key
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// allow takes a token from the client's bucket, if there's one.
+func (l *Limiter) allow(key string, now time.Time) bool {
+	if l.RequestsPerSecond <= 0 {
+		return true
+	}
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+		l.sweep(now)
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.RequestsPerSecond
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep forgets clients whose buckets are full again, so the map doesn't grow with every address ever seen. Must be
+// called with the mutex held.
+func (l *Limiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.Burst+1) / l.RequestsPerSecond * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// acquire takes an in-flight or terminal slot, if there's one free.
+func (l *Limiter) acquire(terminal bool) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if terminal {
+		if l.MaxTerminals > 0 && l.terminals >= l.MaxTerminals {
+			return false
+		}
+		l.terminals++
+		return true
+	}
+
+	if l.MaxInFlight > 0 && l.inFlight >= l.MaxInFlight {
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+// release frees a slot taken by acquire.
+func (l *Limiter) release(terminal bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if terminal {
+		l.terminals--
+	} else {
+		l.inFlight--
+	}
+}
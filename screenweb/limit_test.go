@@ -0,0 +1,53 @@
+package screenweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiterRate(t *testing.T) {
+	l := &Limiter{RequestsPerSecond: 2, Burst: 2}
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	if !l.allow("a", now) || !l.allow("a", now) {
+		t.Fatal("burst refused")
+	}
+	if l.allow("a", now) {
+		t.Error("third request in the same instant allowed")
+	}
+	if !l.allow("b", now) {
+		t.Error("another client was limited too")
+	}
+	if !l.allow("a", now.Add(time.Millisecond*500)) {
+		t.Error("not refilled after half a second")
+	}
+
+	l.allow("c", now.Add(time.Minute)) // Sweeps the idle clients
+	if len(l.buckets) != 1 {
+		t.Errorf("%d buckets left after a minute, want 1", len(l.buckets))
+	}
+}
+
+func TestLimiterInFlight(t *testing.T) {
+	l := &Limiter{MaxInFlight: 1}
+	release, entered := make(chan struct{}), make(chan struct{})
+	h := Tokens{"a": ScopeAll, "b": ScopeAll}.Middleware(l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if client(r) == "" {
+			t.Error("no client key")
+		}
+		close(entered)
+		<-release
+	})))
+
+	go h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?token=a", nil))
+	<-entered
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?token=b", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d while another request is in flight, want 503", rec.Code)
+	}
+	close(release)
+}
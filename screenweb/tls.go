@@ -0,0 +1,57 @@
+package screenweb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+)
+
+// TLSConfig returns a TLS configuration for serving the Handler with the given certificate and key. If clientCAFile
+// is set, clients must present a certificate signed by one of the CAs in it (mutual TLS); combine it with
+// CommonNames.Middleware to decide what each client may do.
+func TLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates in " + clientCAFile)
+		}
+		config.ClientCAs, config.ClientAuth = pool, tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// CommonNames maps the common names of client certificates to what they allow, for servers that verify client
+// certificates (see TLSConfig).
+type CommonNames map[string]Scope
+
+// Middleware only lets requests through that come with a verified client certificate whose common name is listed.
+// Its scope is passed on to the Handler, like with Tokens.Middleware.
+func (c CommonNames) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// VerifiedChains is only set if the certificate was checked against the client CAs
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		scope, ok := c[cn]
+		if !ok {
+			http.Error(w, "client certificate not authorized", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withClient(r.Context(), scope, "cn:"+cn)))
+	})
+}
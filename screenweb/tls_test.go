@@ -0,0 +1,105 @@
+package screenweb
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	screen "github.com/Mexican-Man/go-gnu-screen"
+)
+
+// issue makes a certificate for cn, signed by parent (self-signed if nil), and writes it and its key as PEM files.
+func issue(t *testing.T, dir, cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, cn+".crt"), filepath.Join(dir, cn+".key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return cert, key, certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFile, _ := issue(t, dir, "ca", true, nil, nil)
+	_, _, serverCert, serverKey := issue(t, dir, "server", false, ca, caKey)
+	_, _, opsCert, opsKey := issue(t, dir, "ops", false, ca, caKey)
+	_, _, otherCert, otherKey := issue(t, dir, "intern", false, ca, caKey)
+
+	config, err := TLSConfig(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := CommonNames{"ops": ScopeRead}.Middleware(NewHandler(screen.NewManagerWithRunner(fakeRunner("No Sockets found in /run/screen/S-root.\n"))))
+	srv := httptest.NewUnstartedServer(h)
+	srv.TLS = config
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // The handshake without a certificate fails on purpose
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(certFile, keyFile string) (int, error) {
+		tlsConfig := &tls.Config{RootCAs: roots}
+		if certFile != "" {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		res, err := client.Get(srv.URL + "/api/sessions")
+		if err != nil {
+			return 0, err
+		}
+		res.Body.Close()
+		return res.StatusCode, nil
+	}
+
+	if code, err := get(opsCert, opsKey); err != nil || code != http.StatusOK {
+		t.Errorf("ops: got %d, %v", code, err)
+	}
+	if code, err := get(otherCert, otherKey); err != nil || code != http.StatusForbidden {
+		t.Errorf("unlisted common name: got %d, %v", code, err)
+	}
+	if _, err := get("", ""); err == nil {
+		t.Error("connected without a client certificate")
+	}
+}
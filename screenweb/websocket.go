@@ -0,0 +1,214 @@
+package screenweb
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// WebSocket opcodes, see RFC 6455 section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// maxMessageSize limits what a client can make us buffer.
+const maxMessageSize = 1 << 20
+
+var errMessageTooBig = errors.New("websocket message too big")
+
+// wsConn is the server side of a WebSocket connection. It's just enough of RFC 6455 to bridge a terminal: no
+// extensions or subprotocols.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	writeMutex sync.Mutex
+}
+
+// upgrade switches an HTTP request to the WebSocket protocol. Cross-origin requests are refused, so other websites
+// can't open terminals with the browser's credentials. On failure, an error response has already been written.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	fail := func(code int, msg string) (*wsConn, error) {
+		http.Error(w, msg, code)
+		return nil, errors.New(msg)
+	}
+
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		return fail(http.StatusBadRequest, "not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return fail(http.StatusUpgradeRequired, "unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return fail(http.StatusBadRequest, "missing Sec-WebSocket-Key")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return fail(http.StatusForbidden, "cross-origin websocket refused")
+		}
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return fail(http.StatusInternalServerError, "connection can't be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return fail(http.StatusInternalServerError, err.Error())
+	}
+
+	sum := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err = rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, r: rw.Reader}, nil
+}
+
+// headerContains reports whether a comma separated header contains token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage reads the next text or binary message, answering pings along the way. A close from the client returns
+// io.EOF.
+func (c *wsConn) ReadMessage() (op byte, msg []byte, err error) {
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case opPing:
+			if err = c.WriteMessage(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.WriteMessage(opClose, payload)
+			return 0, nil, io.EOF
+		case opText, opBinary:
+			if msg != nil {
+				return 0, nil, errors.New("websocket message interrupted by another")
+			}
+			op, msg = frameOp, payload
+		case opContinuation:
+			if msg == nil {
+				return 0, nil, errors.New("websocket continuation without a message")
+			}
+			if len(msg)+len(payload) > maxMessageSize {
+				return 0, nil, errMessageTooBig
+			}
+			msg = append(msg, payload...)
+		default:
+			return 0, nil, errors.New("unknown websocket opcode")
+		}
+
+		if fin {
+			return op, msg, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, and unmasks its payload.
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.r, header[:]); err != nil {
+		return
+	}
+	fin, op = header[0]&0x80 != 0, header[0]&0x0f
+	if header[1]&0x80 == 0 {
+		err = errors.New("websocket frame from client isn't masked")
+		return
+	}
+
+	size := uint64(header[1] & 0x7f)
+	switch size {
+	case 126:
+		var b [2]byte
+		_, err = io.ReadFull(c.r, b[:])
+		size = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		_, err = io.ReadFull(c.r, b[:])
+		size = binary.BigEndian.Uint64(b[:])
+	}
+	if err != nil {
+		return
+	}
+	if size > maxMessageSize {
+		err = errMessageTooBig
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.r, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(c.r, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// WriteMessage sends a message in a single frame. It's safe to call concurrently.
+func (c *wsConn) WriteMessage(op byte, msg []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	header := []byte{0x80 | op}
+	switch {
+	case len(msg) < 126:
+		header = append(header, byte(len(msg)))
+	case len(msg) <= 0xffff:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(msg)))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(msg)))
+	}
+
+	if _, err := c.conn.Write(append(header, msg...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection, telling the client first.
+func (c *wsConn) Close() error {
+	c.WriteMessage(opClose, []byte{0x03, 0xe8}) // 1000, normal closure
+	return c.conn.Close()
+}
@@ -0,0 +1,131 @@
+package screenweb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// dialWS opens a WebSocket to an httptest server by hand, and returns the connection after the handshake.
+func dialWS(t *testing.T, srv *httptest.Server, path string, header string) (net.Conn, *bufio.Reader, string) {
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	req := "GET " + path + " HTTP/1.1\r\nHost: " + srv.Listener.Addr().String() + "\r\nUpgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		header + "\r\n"
+	if _, err = conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, r, res.Status + " " + res.Header.Get("Sec-WebSocket-Accept")
+}
+
+// writeClientFrame writes a masked frame, like a browser does.
+func writeClientFrame(t *testing.T, w io.Writer, fin bool, op byte, payload []byte) {
+	b := []byte{op, 0x80 | byte(len(payload)), 1, 2, 3, 4}
+	if fin {
+		b[0] |= 0x80
+	}
+	for i, c := range payload {
+		b = append(b, c^b[2+i%4])
+	}
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readServerFrame reads a short unmasked frame.
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	size := int(header[1] & 0x7f)
+	if size == 126 {
+		var b [2]byte
+		io.ReadFull(r, b[:])
+		size = int(binary.BigEndian.Uint16(b[:]))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			op, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(op, []byte(strings.ToUpper(string(msg))))
+		}
+	}))
+	defer srv.Close()
+
+	conn, r, status := dialWS(t, srv, "/", "")
+	// The accept key for the sample nonce of RFC 6455
+	if status != "101 Switching Protocols s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("got %q", status)
+	}
+
+	// A fragmented message with a ping in between
+	writeClientFrame(t, conn, false, opText, []byte("hel"))
+	writeClientFrame(t, conn, true, opPing, []byte("?"))
+	writeClientFrame(t, conn, true, opContinuation, []byte("lo"))
+
+	if op, payload := readServerFrame(t, r); op != opPong || string(payload) != "?" {
+		t.Errorf("got %x %q, want a pong", op, payload)
+	}
+	if op, payload := readServerFrame(t, r); op != opText || string(payload) != "HELLO" {
+		t.Errorf("got %x %q, want HELLO", op, payload)
+	}
+
+	long := strings.Repeat("x", 300)
+	writeClientFrame(t, conn, true, opBinary, []byte(long[:125]))
+	if _, payload := readServerFrame(t, r); len(payload) != 125 {
+		t.Errorf("got %d bytes back", len(payload))
+	}
+
+	writeClientFrame(t, conn, true, opClose, nil)
+	if op, _ := readServerFrame(t, r); op != opClose {
+		t.Errorf("got %x, want a close", op)
+	}
+}
+
+func TestWebSocketCrossOrigin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, err := upgrade(w, r); err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	if _, _, status := dialWS(t, srv, "/", "Origin: https://evil.example\r\n"); !strings.HasPrefix(status, "403") {
+		t.Errorf("got %q, want 403", status)
+	}
+	if _, _, status := dialWS(t, srv, "/", "Origin: http://"+srv.Listener.Addr().String()+"\r\n"); !strings.HasPrefix(status, "101") {
+		t.Errorf("got %q for the same origin, want 101", status)
+	}
+}
@@ -0,0 +1,63 @@
+package screen
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ShellDialect is the kind of shell a screen runs, which decides how StuffScript wraps a script.
+type ShellDialect int
+
+const (
+	ShellPOSIX      ShellDialect = iota // sh, bash, dash, zsh, ksh
+	ShellFish                           // fish
+	ShellPowerShell                     // PowerShell
+)
+
+const (
+	scriptChunk = 512  // Bytes of a script StuffScript stuffs at once
+	scriptLine  = 2048 // Base64 characters of a script typed per line, so no line gets cut off by the terminal
+)
+
+// StuffScript types a multi-line script into the screen's shell as a single command, and runs it. The script runs in
+// the shell itself (not a child), so it can change its directory or variables, and nothing in it needs escaping. It's
+// typed as base64, so tabs and control characters in it can't set off completion or line editing on the way; long
+// scripts are spread over several lines.
+func (s Screen) StuffScript(script string, shell ShellDialect) error {
+	cmd, err := wrapScript(script, shell)
+	if err != nil {
+		return err
+	}
+	_, err = s.Writer(Throttle{Chunk: scriptChunk}).Write([]byte(cmd))
+	return err
+}
+
+// wrapScript returns the command running script in the given shell. Its base64 is broken into quoted pieces of
+// scriptLine characters, one per line, which the shell joins back together.
+func wrapScript(script string, shell ShellDialect) (string, error) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(script))
+	switch shell {
+	case ShellPOSIX:
+		return `eval "$(printf %s '` + splitScript(encoded, "'\\\n'") + `' | base64 -d)"` + "\n", nil
+	case ShellFish:
+		return "echo '" + splitScript(encoded, "'\\\n'") + "' | base64 -d | source\n", nil
+	case ShellPowerShell:
+		return "Invoke-Expression ([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('" + splitScript(encoded, "' +\n'") + "')))\n", nil
+	}
+	return "", &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("unknown shell dialect " + strconv.Itoa(int(shell)))}
+}
+
+// splitScript puts sep between every scriptLine characters of encoded.
+func splitScript(encoded, sep string) string {
+	var b strings.Builder
+	for len(encoded) > scriptLine {
+		b.WriteString(encoded[:scriptLine])
+		b.WriteString(sep)
+		encoded = encoded[scriptLine:]
+	}
+	b.WriteString(encoded)
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package screen
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWrapScript(t *testing.T) {
+	script := "x='it''s'\ncd /\necho \"$x $PWD\" `echo back` \\\nGO_GNU_SCREEN_EOF"
+	cmd, err := wrapScript(script, ShellPOSIX)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run in the shell itself, so what the script sets is still there afterwards
+	out, err := exec.Command("sh", "-c", cmd+`echo "after $x $PWD"`).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %v", out, err)
+	}
+	if want := "its / back GO_GNU_SCREEN_EOF\nafter its /\n"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	if _, err = wrapScript(script, ShellDialect(42)); err == nil {
+		t.Error("expected an error for an unknown dialect")
+	}
+}
+
+func TestWrapScriptBase64(t *testing.T) {
+	cmd, _ := wrapScript("echo hi\n", ShellFish)
+	if want := "echo 'ZWNobyBoaQo=' | base64 -d | source\n"; cmd != want {
+		t.Errorf("got %q, want %q", cmd, want)
+	}
+}
+
+func TestWrapScriptTabs(t *testing.T) {
+	// Typed into an interactive shell, a tab would set off completion, so none may get through
+	script := "if true; then\n\tfor i in 1 2; do\n\t\tprintf '%s\\t' \"$i\"\n\tdone\nfi\n"
+	cmd, err := wrapScript(script, ShellPOSIX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := strings.IndexFunc(strings.TrimSuffix(cmd, "\n"), func(r rune) bool { return r < ' ' || r == 0x7f }); i >= 0 {
+		t.Errorf("command has control character %q at %d: %q", cmd[i], i, cmd)
+	}
+
+	out, err := exec.Command("sh", "-c", cmd).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %v", out, err)
+	}
+	if want := "1\t2\t"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestWrapScriptLong(t *testing.T) {
+	// A terminal line holds 4095 characters, anything past that is cut off before the shell sees it
+	var script strings.Builder
+	for i := 0; script.Len() < 8<<10; i++ {
+		script.WriteString("echo line " + strconv.Itoa(i) + "\n")
+	}
+	for _, shell := range []ShellDialect{ShellPOSIX, ShellFish, ShellPowerShell} {
+		cmd, err := wrapScript(script.String(), shell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(cmd, "\n") {
+			if len(line) >= 4095 {
+				t.Errorf("dialect %d: line of %d characters", shell, len(line))
+			}
+		}
+	}
+
+	cmd, _ := wrapScript(script.String(), ShellPOSIX)
+	for _, sh := range []string{"sh", "bash"} {
+		if _, err := exec.LookPath(sh); err != nil {
+			continue
+		}
+		out, err := exec.Command(sh, "-c", cmd).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %s: %v", sh, out, err)
+		}
+		if want := strings.ReplaceAll(script.String(), "echo ", ""); string(out) != want {
+			t.Errorf("%s: got %d bytes, want %d", sh, len(out), len(want))
+		}
+	}
+}
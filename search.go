@@ -0,0 +1,141 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SearchField is metadata of a screen that Search looks in. Fields combine with |.
+type SearchField int
+
+const (
+	SearchName  SearchField = 1 << iota // The screen's name
+	SearchTitle                         // Titles of its windows
+	SearchTag                           // Its tags, see Manager.Tags
+	SearchOwner                         // The user it belongs to
+
+	SearchAll = SearchName | SearchTitle | SearchTag | SearchOwner
+)
+
+// searchWeights is how much a match in each field counts towards a result's score.
+var searchWeights = map[SearchField]int{SearchName: 8, SearchTag: 4, SearchTitle: 2, SearchOwner: 1}
+
+var searchFieldNames = []string{"name", "title", "tag", "owner"}
+
+func (f SearchField) String() string {
+	var names []string
+	for i, name := range searchFieldNames {
+		if f&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Query is what Search looks for.
+type Query struct {
+	Pattern *regexp.Regexp // Required; use regexp.QuoteMeta to search for plain text
+	Fields  SearchField    // Where to look, everywhere if zero
+	Limit   int            // Most results returned, no limit if zero
+}
+
+// SearchMatch is a piece of metadata a Query matched.
+type SearchMatch struct {
+	Field SearchField
+	Text  string // The whole name, title, tag or owner that matched
+}
+
+// SearchResult is a screen a Query matched, and how well.
+type SearchResult struct {
+	Screen  Screen
+	Score   int // Higher is better. Names count most, then tags, titles and owners; whole matches count double.
+	Matches []SearchMatch
+}
+
+// Search returns the screens whose metadata matches q, best first. Looking in titles costs a query of every screen.
+func Search(q Query) ([]SearchResult, error) {
+	return local.Search(q)
+}
+
+// Search returns the screens on the Manager's host whose metadata matches q, best first. See Search.
+func (m *Manager) Search(q Query) ([]SearchResult, error) {
+	if q.Pattern == nil {
+		return nil, &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("query has no pattern")}
+	}
+	if q.Fields == 0 {
+		q.Fields = SearchAll
+	}
+
+	screens, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var owner string
+	if q.Fields&SearchOwner != 0 && len(screens) > 0 {
+		owner = m.socketOwner()
+	}
+
+	var res []SearchResult
+	for _, s := range screens {
+		r := SearchResult{Screen: s}
+		match := func(field SearchField, text string) {
+			if q.Fields&field == 0 || text == "" {
+				return
+			}
+			loc := q.Pattern.FindStringIndex(text)
+			if loc == nil {
+				return
+			}
+			score := searchWeights[field]
+			if loc[0] == 0 && loc[1] == len(text) {
+				score *= 2
+			}
+			r.Score += score
+			r.Matches = append(r.Matches, SearchMatch{Field: field, Text: text})
+		}
+
+		match(SearchName, s.Name)
+		if q.Fields&SearchTag != 0 && m.Tags != nil {
+			for _, tag := range m.Tags(s) {
+				match(SearchTag, tag)
+			}
+		}
+		if q.Fields&SearchTitle != 0 {
+			windows, _ := s.Windows() // Screens may go away while searching
+			for _, w := range windows {
+				match(SearchTitle, w.Title)
+			}
+		}
+		match(SearchOwner, owner)
+
+		if r.Score > 0 {
+			res = append(res, r)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Screen.Name < res[j].Screen.Name
+	})
+	if q.Limit > 0 && len(res) > q.Limit {
+		res = res[:q.Limit]
+	}
+	return res, nil
+}
+
+// socketOwner returns the user the screens on the Manager's host belong to, from the name of their socket directory
+// ("S-<user>"), or the user the Manager's commands run as. It's empty if neither is known.
+func (m *Manager) socketOwner() string {
+	if dir, err := m.FindSocketDir(); err == nil && strings.HasPrefix(path.Base(dir), "S-") {
+		return strings.TrimPrefix(path.Base(dir), "S-")
+	}
+	_, _, user, _ := m.hostEnv()
+	return user
+}
@@ -0,0 +1,46 @@
+package screen
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	const screen = "/usr/bin/screen -S "
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{
+		"screen -ls": fakeList,
+		screen + "4250.deploy europe-west 1 -Q windows":  "0$ deploy\n",
+		screen + "4251.deploy europe-west 10 -Q windows": "0$ bash  1-$ europe tail\n",
+		screen + "4242.a+b -Q windows":                   "0*$ europe-west\n",
+	}})
+	m.Tags = func(s Screen) []string {
+		if s.Name == "a+b" {
+			return []string{"europe"}
+		}
+		return nil
+	}
+
+	res, err := m.Search(Query{Pattern: regexp.MustCompile("europe")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range res {
+		got = append(got, r.Screen.Name)
+	}
+	// A whole tag counts as much as a name, matches add up, and ties go by name
+	if want := "a+b|deploy europe-west 10|deploy europe-west 1"; strings.Join(got, "|") != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if res[0].Score != 10 || res[2].Score != 8 || len(res[0].Matches) != 2 {
+		t.Errorf("got %+v", res)
+	}
+
+	if res, err = m.Search(Query{Pattern: regexp.MustCompile("^root$"), Fields: SearchOwner, Limit: 1}); err != nil || len(res) != 1 {
+		t.Errorf("got %+v, %v, want the owner to match", res, err)
+	}
+	if _, err = m.Search(Query{}); err == nil {
+		t.Error("searched without a pattern")
+	}
+}
@@ -0,0 +1,53 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// secretRegister is the paste register StuffSecretEnv passes secrets through. It's cleared afterwards.
+const secretRegister = "s"
+
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// StuffSecretEnv exports an environment variable in the shell of the screen's current window, without the value
+// showing up anywhere: not on the screen or in its scrollback (the shell reads it with "read -s"), not in logs or
+// captures (logging is off meanwhile), not in the shell's history, and not in any process' arguments (screen reads it
+// from a private file into a paste register). The shell has to be a POSIX one sitting at its prompt.
+func (s Screen) StuffSecretEnv(name, value string) error {
+	if !envNameRegexp.MatchString(name) {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid environment variable name " + name)}
+	}
+	if strings.ContainsAny(value, "\n\x00") {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("secret contains a newline or NUL")}
+	}
+
+	file, err := s.m().tempFile()
+	if err != nil {
+		return err
+	}
+	defer s.m().remove(file)
+	if err = s.m().writeFile(file, []byte(value+"\n")); err != nil {
+		return err
+	}
+
+	if err = s.builtinTemplateArgs("log", "off"); err != nil {
+		return err
+	}
+	defer s.resumeLog()
+
+	// The leading space keeps the line out of the history of most shells
+	if err = s.Stuff(" IFS= read -rs " + name + " && export " + name + "\n"); err != nil {
+		return err
+	}
+	time.Sleep(s.m().outputPoll()) // Give read a moment to switch off echoing
+
+	return s.Batch(func(b *Batch) {
+		b.Command("readreg", secretRegister, file)
+		b.Command("paste", secretRegister)
+		b.Command("register", secretRegister, "")
+	})
+}
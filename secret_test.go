@@ -0,0 +1,60 @@
+package screen
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCommander answers commands like fakeRunner, but streams them for real.
+type fakeCommander struct {
+	*fakeRunner
+}
+
+func (fakeCommander) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+func TestStuffSecretEnv(t *testing.T) {
+	const screen = "/usr/bin/screen -S 4242.a+b -X "
+	spool := t.TempDir()
+	file := filepath.Join(spool, "secret")
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b":                                        fakeList,
+		"mktemp -d -t go-gnu-screen-XXXXXXXX":                   spool + "\n",
+		"mktemp -p " + spool:                                    file + "\n",
+		screen + "log off":                                      "",
+		screen + "stuff  IFS= read -rs TOKEN && export TOKEN\n": "",
+		screen + "eval readreg 's' '" + file + "' paste 's' register 's' ''": "",
+		screen + "logfile screenlog.%n":                                      "",
+		"rm -f " + file:                                                      "",
+	}}
+	m := NewManagerWithRunner(fakeCommander{r})
+	m.Defaults.OutputPoll = time.Millisecond
+	s, err := m.Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.StuffSecretEnv("TOKEN", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range r.ran {
+		if strings.Contains(line, "hunter2") {
+			t.Errorf("secret passed as an argument: %q", line)
+		}
+	}
+	if ran := strings.Join(r.ran, "\n"); !strings.Contains(ran, "rm -f "+file) || !strings.Contains(ran, "eval readreg") {
+		t.Errorf("ran %q", ran)
+	}
+
+	if err = s.StuffSecretEnv("TOKEN; rm -rf ~", "x"); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if err = s.StuffSecretEnv("TOKEN", "a\nb"); err == nil {
+		t.Error("expected an error for a multi-line value")
+	}
+}
@@ -0,0 +1,87 @@
+package screen
+
+import (
+	"context"
+	"time"
+)
+
+// SessionDiff is how the set of screens changed between two listings, see DiffSessions.
+type SessionDiff struct {
+	Added   []Screen
+	Removed []Screen
+	Changed []Screen
+}
+
+// DiffSessions compares two listings of screens by name. Screens only in curr are added, screens only in prev are
+// removed, and screens in both whose process differs (the name was reused by a new screen) are changed, as they are in
+// curr. Each keeps the order of the listing it comes from.
+func DiffSessions(prev, curr []Screen) (added, removed, changed []Screen) {
+	before := make(map[string]Screen, len(prev))
+	for _, s := range prev {
+		before[s.Name] = s
+	}
+	after := make(map[string]bool, len(curr))
+
+	for _, s := range curr {
+		after[s.Name] = true
+		old, ok := before[s.Name]
+		switch {
+		case !ok:
+			added = append(added, s)
+		case screenPID(old) != screenPID(s) || old.startTime != s.startTime:
+			changed = append(changed, s)
+		}
+	}
+	for _, s := range prev {
+		if !after[s.Name] {
+			removed = append(removed, s)
+		}
+	}
+	return
+}
+
+// screenPID returns the PID of a screen's process, 0 if it's unknown.
+func screenPID(s Screen) int {
+	if s.Process == nil {
+		return 0
+	}
+	return s.Process.Pid
+}
+
+// WatchSessionDiffs lists the Manager's screens every interval until ctx is done, and sends how they changed to
+// diffs, for controllers reconciling the screens they want with the ones there are. The first diff has every
+// existing screen as added; after that, only listings that changed something are sent. It returns when ctx is done,
+// or listing fails.
+func (m *Manager) WatchSessionDiffs(ctx context.Context, interval time.Duration, diffs chan<- SessionDiff) error {
+	ctx, cancel, err := m.bind(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	var known []Screen
+	first := true
+	for {
+		screens, err := m.ListSessions()
+		if err != nil {
+			return err
+		}
+
+		var d SessionDiff
+		d.Added, d.Removed, d.Changed = DiffSessions(known, screens)
+		if first || len(d.Added)+len(d.Removed)+len(d.Changed) > 0 {
+			select {
+			case diffs <- d:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		known, first = screens, false
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSessions(t *testing.T) {
+	screen := func(name string, pid int) Screen {
+		return Screen{Name: name, Process: &os.Process{Pid: pid}}
+	}
+	prev := []Screen{screen("kept", 1), screen("gone", 2), screen("reused", 3)}
+	curr := []Screen{screen("reused", 4), screen("kept", 1), screen("new", 5)}
+
+	added, removed, changed := DiffSessions(prev, curr)
+	names := func(screens []Screen) (res []string) {
+		for _, s := range screens {
+			res = append(res, s.Name)
+		}
+		return
+	}
+	if got := [][]string{names(added), names(removed), names(changed)}; !reflect.DeepEqual(got, [][]string{{"new"}, {"gone"}, {"reused"}}) {
+		t.Errorf("got %q", got)
+	}
+	if changed[0].Process.Pid != 4 {
+		t.Errorf("changed screen is %+v, want the current one", changed[0])
+	}
+}
+
+func TestWatchSessionDiffs(t *testing.T) {
+	m := NewManagerWithRunner(&listingsRunner{
+		"\t1.old\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+		"\t1.old\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+		"\t2.new\t(Detached)\n1 Socket in /run/screen/S-root.\n",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	diffs := make(chan SessionDiff)
+	done := make(chan error)
+	go func() { done <- m.WatchSessionDiffs(ctx, time.Millisecond, diffs) }()
+
+	first, second := <-diffs, <-diffs
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if len(first.Added) != 1 || first.Added[0].Name != "old" {
+		t.Errorf("first diff is %+v", first)
+	}
+	if len(second.Added) != 1 || second.Added[0].Name != "new" || len(second.Removed) != 1 || second.Removed[0].Name != "old" {
+		t.Errorf("second diff is %+v", second)
+	}
+}
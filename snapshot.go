@@ -0,0 +1,71 @@
+package screen
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotPrefix = "snapshot-"
+	snapshotLayout = "20060102-150405.000" // Sorts by time, and is safe in file names
+)
+
+// SnapshotEvery takes a hardcopy of the screen every interval until ctx is done, making a time-lapse of what it
+// displayed. Snapshots are named "snapshot-<time>.txt" and written to dir on the Manager's host, which must exist. If
+// keep is positive, only the newest keep snapshots in dir are kept.
+//
+// A failing hardcopy, i.e. because the screen is gone, stops it and is returned.
+func (s Screen) SnapshotEvery(ctx context.Context, interval time.Duration, dir string, keep int) error {
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for {
+		name := path.Join(dir, snapshotPrefix+time.Now().Format(snapshotLayout)+".txt")
+		if err := s.Hardcopy(name, false); err != nil {
+			return err
+		}
+		if keep > 0 {
+			if err := s.m().pruneSnapshots(dir, keep); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pruneSnapshots removes all but the newest keep snapshots from dir on the Manager's host. Other files are left alone.
+func (m *Manager) pruneSnapshots(dir string, keep int) error {
+	names, err := m.listDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []string
+	for _, name := range names {
+		if strings.HasPrefix(name, snapshotPrefix) && strings.HasSuffix(name, ".txt") {
+			snapshots = append(snapshots, name)
+		}
+	}
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	sort.Strings(snapshots) // Oldest first
+	for _, name := range snapshots[:len(snapshots)-keep] {
+		if err = m.remove(path.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
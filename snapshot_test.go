@@ -0,0 +1,35 @@
+package screen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPruneSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"snapshot-20261015-100000.000.txt",
+		"snapshot-20261015-100001.000.txt",
+		"snapshot-20261015-100002.000.txt",
+		"notes.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := NewManager().pruneSnapshots(dir, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := NewManager().listDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"notes.txt", "snapshot-20261015-100001.000.txt", "snapshot-20261015-100002.000.txt"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,130 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// FindSocketDir returns the socket directory of the local machine, see Manager.FindSocketDir.
+func FindSocketDir() (string, error) {
+	return local.FindSocketDir()
+}
+
+// FindSocketDir returns the directory screen keeps the current user's sockets in on the Manager's host: the
+// Manager's SocketDir if it's set, otherwise the directory "screen -ls" names, otherwise $SCREENDIR or the first
+// existing one of the layouts distributions use (/run/screen/S-<user>, /var/run/screen/S-<user>,
+// /tmp/screens/S-<user>, /tmp/uscreens/S-<user>, ~/.screen). If there is none, i.e. because no screen ever ran,
+// ErrNotExist type is returned.
+func (m *Manager) FindSocketDir() (string, error) {
+	out, _ := m.combined("screen", "-ls")
+	return m.findSocketDir(string(out))
+}
+
+// findSocketDir finds the socket directory like FindSocketDir, given the output of "screen -ls".
+func (m *Manager) findSocketDir(listing string) (string, error) {
+	if m.SocketDir != "" {
+		return m.SocketDir, nil
+	}
+	if dir := screenparse.ParseSocketDir(listing); dir != "" {
+		return dir, nil
+	}
+
+	candidates, exclusive, err := m.socketDirCandidates()
+	if err != nil {
+		return "", err
+	}
+	if exclusive {
+		return candidates[0], nil
+	}
+	for _, dir := range candidates {
+		if m.stat(dir) == nil {
+			return dir, nil
+		}
+	}
+	return "", &os.SyscallError{Syscall: os.ErrNotExist.Error(), Err: errors.New("socket directory not found")}
+}
+
+// socketDirCandidates lists where the current user's sockets may be on the Manager's host, most likely first. With
+// $SCREENDIR set, screen uses nothing else, which exclusive reports.
+func (m *Manager) socketDirCandidates() (res []string, exclusive bool, err error) {
+	screenDir, home, user, err := m.hostEnv()
+	if err != nil {
+		return nil, false, err
+	}
+	if screenDir != "" {
+		return []string{screenDir}, true, nil
+	}
+
+	if user != "" {
+		for _, dir := range []string{"/run/screen", "/var/run/screen", "/tmp/screens", "/tmp/uscreens"} {
+			res = append(res, path.Join(dir, "S-"+user))
+		}
+	}
+	if home != "" {
+		res = append(res, path.Join(home, ".screen"))
+	}
+	return res, false, nil
+}
+
+// hostEnvScript prints what hostEnv returns, a line each.
+const hostEnvScript = `printf '%s\n%s\n%s\n' "$SCREENDIR" "$HOME" "$(id -un)"`
+
+// hostEnv returns $SCREENDIR, the home directory and the name of the user the Manager's commands run as. With an Env,
+// screen sees its $SCREENDIR and $HOME instead.
+func (m *Manager) hostEnv() (screenDir, home, user string, err error) {
+	if screenDir, home, user, err = m.hostEnvInherited(); err != nil {
+		return
+	}
+	if dir, isolated := m.lookupEnv("SCREENDIR"); isolated {
+		screenDir = dir
+		if h, _ := m.lookupEnv("HOME"); h != "" {
+			home = h
+		}
+	}
+	return
+}
+
+// hostEnvInherited returns what hostEnv does, as screen would see it without the Manager's Env.
+func (m *Manager) hostEnvInherited() (screenDir, home, user string, err error) {
+	if m.isLocal() {
+		home, _ = os.UserHomeDir()
+		return os.Getenv("SCREENDIR"), home, username, nil
+	}
+
+	out, _, err := m.run(context.Background(), "sh", "-c", hostEnvScript)
+	if err != nil {
+		return "", "", "", err
+	}
+	lines := strings.Split(string(out), "\n")
+	if len(lines) < 3 || lines[2] == "" {
+		return "", "", "", errors.New("unexpected environment: " + string(out))
+	}
+	return lines[0], lines[1], lines[2], nil
+}
+
+// emptySocketDir reports whether the socket directory is known to hold no sockets, given the output of "screen -ls".
+// A socket directory that doesn't exist is empty too.
+func (m *Manager) emptySocketDir(listing string) bool {
+	dir, err := m.findSocketDir(listing)
+	if IsNotFound(err) {
+		return true
+	} else if err != nil {
+		return false
+	}
+
+	names, err := m.listDir(dir)
+	if err != nil {
+		return m.stat(dir) != nil
+	}
+	for _, name := range names {
+		if !strings.HasPrefix(name, ".") { // Screen ignores hidden files, like leases
+			return false
+		}
+	}
+	return true
+}
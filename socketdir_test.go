@@ -0,0 +1,90 @@
+package screen
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFindSocketDir(t *testing.T) {
+	const env = "sh -c " + hostEnvScript
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    string
+	}{
+		{"listing", map[string]string{"screen -ls": fakeList}, "/run/screen/S-root"},
+		{"tmp", map[string]string{
+			"screen -ls":                 "Keine Sockets gefunden.\n",
+			env:                          "\n/home/joe\njoe\n",
+			"test -e /tmp/screens/S-joe": "",
+		}, "/tmp/screens/S-joe"},
+		{"home", map[string]string{
+			"screen -ls":                "Keine Sockets gefunden.\n",
+			env:                         "\n/home/joe\njoe\n",
+			"test -e /home/joe/.screen": "",
+		}, "/home/joe/.screen"},
+		{"SCREENDIR", map[string]string{
+			"screen -ls": "Keine Sockets gefunden.\n",
+			env:          "/srv/screens\n/home/joe\njoe\n",
+		}, "/srv/screens"},
+	}
+
+	for _, tt := range tests {
+		dir, err := NewManagerWithRunner(&fakeRunner{outputs: tt.outputs}).FindSocketDir()
+		if err != nil || dir != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, dir, err, tt.want)
+		}
+	}
+
+	m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"screen -ls": fakeList}})
+	m.SocketDir = "/custom"
+	if dir, _ := m.FindSocketDir(); dir != "/custom" {
+		t.Errorf("got %q, want the override", dir)
+	}
+
+	m = NewManagerWithRunner(&fakeRunner{outputs: map[string]string{env: "\n/home/joe\njoe\n"}})
+	_, err := m.FindSocketDir()
+	if sysErr, ok := err.(*os.SyscallError); !ok || sysErr.Syscall != os.ErrNotExist.Error() {
+		t.Errorf("got %v, want ErrNotExist type", err)
+	}
+	if !m.emptySocketDir("") {
+		t.Error("a missing socket directory should count as empty")
+	}
+}
+
+func TestEmptySocketDir(t *testing.T) {
+	tests := []struct {
+		ls   string
+		want bool
+	}{
+		{"", true},
+		{".4242.build.lease\n", true},
+		{"4242.build\n", false},
+	}
+
+	for _, tt := range tests {
+		m := NewManagerWithRunner(&fakeRunner{outputs: map[string]string{"ls -1A /srv/screens": tt.ls}})
+		m.SocketDir = "/srv/screens"
+		if got := m.emptySocketDir(""); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.ls, got, tt.want)
+		}
+	}
+}
+
+func TestSocketDirEnv(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{"screen -ls": ""}}
+	m := NewManagerWithRunner(r)
+	m.Env = []string{"PATH=/usr/bin:/bin", "SCREENDIR=/srv/screens", "LC_ALL=C"}
+	r.outputs["env -i "+strings.Join(m.Env, " ")+" screen -ls"] = "No Sockets found in /srv/screens.\n"
+	r.outputs["sh -c "+hostEnvScript] = "/home/me/.screens\n/home/me\nme\n"
+
+	if dir, err := m.FindSocketDir(); err != nil || dir != "/srv/screens" {
+		t.Errorf("got %q, %v", dir, err)
+	}
+
+	// Without a listing that says, $SCREENDIR of the Env wins over the host's
+	if candidates, exclusive, err := m.socketDirCandidates(); err != nil || !exclusive || candidates[0] != "/srv/screens" {
+		t.Errorf("got %q, %v, %v", candidates, exclusive, err)
+	}
+}
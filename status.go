@@ -0,0 +1,46 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// SessionState is whether anyone is attached to a screen, as reported by "screen -ls".
+type SessionState = screenparse.SessionState
+
+const (
+	StateUnknown  = screenparse.StateUnknown
+	StateAttached = screenparse.StateAttached
+	StateDetached = screenparse.StateDetached
+	StateDead     = screenparse.StateDead
+)
+
+// Status is everything "screen -ls" knows about a screen.
+type Status = screenparse.Status
+
+// Status returns what "screen -ls" says about the screen, plus the permissions of its socket. If the screen is gone,
+// ErrNotExist type is returned; if it can't be listed, why.
+func (s Screen) Status() (Status, error) {
+	out, err := s.m().combined("screen", "-ls", s.Name)
+	for _, e := range screenparse.ParseList(string(out)) {
+		if e.Name != s.Name || (s.Process != nil && e.PID != s.Process.Pid) {
+			continue
+		}
+
+		if dir, err := s.m().findSocketDir(string(out)); err == nil {
+			e.Status.SocketPath = path.Join(dir, strconv.Itoa(e.PID)+"."+e.Name)
+			e.Status.SocketMode, _ = s.m().fileMode(e.Status.SocketPath)
+		}
+		return e.Status, nil
+	}
+
+	// Failing to list isn't the same as not being listed
+	if err = listError(out, err); err != nil && !errors.Is(err, ErrUnparseable) {
+		return Status{}, err
+	}
+	return Status{}, os.ErrNotExist
+}
@@ -0,0 +1,107 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// ErrStillRunning is returned by Terminate when a screen outlived its StopPolicy.
+var ErrStillRunning = errors.New("screen is still running")
+
+// StopPolicy is how Terminate shuts a screen down: each signal in turn goes to everything running in the screen's
+// windows, and Terminate waits for the screen to end before sending the next.
+type StopPolicy struct {
+	Signals     []syscall.Signal
+	Waits       []time.Duration // How long to wait after each signal; signals without one wait as long as the last
+	FinallyQuit bool            // Quit the screen if it's still there after the last signal, rather than failing
+}
+
+var (
+	// StopShell hangs up interactive shells, and kills what's left shortly after.
+	StopShell = StopPolicy{
+		Signals:     []syscall.Signal{syscall.SIGHUP, syscall.SIGKILL},
+		Waits:       []time.Duration{time.Second * 2},
+		FinallyQuit: true,
+	}
+	// StopDatabase gives servers time to finish requests and flush to disk, before being killed.
+	StopDatabase = StopPolicy{
+		Signals:     []syscall.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL},
+		Waits:       []time.Duration{time.Second * 30, time.Second * 30, time.Second * 5},
+		FinallyQuit: true,
+	}
+)
+
+// wait returns how long to wait after the i-th signal.
+func (p StopPolicy) wait(i int) time.Duration {
+	switch {
+	case len(p.Waits) == 0:
+		return 0
+	case i < len(p.Waits):
+		return p.Waits[i]
+	default:
+		return p.Waits[len(p.Waits)-1]
+	}
+}
+
+// Terminate shuts the screen down as policy says, and returns once it's gone. If it's still there after the last
+// signal, it's quit if the policy says so, otherwise ErrStillRunning is returned.
+func (s Screen) Terminate(ctx context.Context, policy StopPolicy) error {
+	if err := s.checkProcess(); err != nil {
+		return err
+	}
+	if err := s.m().Policy.checkSignal(s.target()); err != nil {
+		return err
+	}
+
+	for i, sig := range policy.Signals {
+		// Collect the whole tree first, children may get reparented once their parent dies
+		pids, err := s.m().descendants(s.Process.Pid)
+		if err != nil {
+			return err
+		}
+		if len(pids) == 0 {
+			break // Nothing left in the windows, screen is on its way out
+		}
+		if err = s.m().signal(s.target(), sig, pids...); err != nil {
+			return err
+		}
+
+		if gone, err := s.waitGone(ctx, policy.wait(i)); gone || err != nil {
+			return err
+		}
+	}
+
+	if gone, err := s.waitGone(ctx, 0); gone || err != nil {
+		return err
+	}
+	if !policy.FinallyQuit {
+		return fmt.Errorf("%w after %d signals", ErrStillRunning, len(policy.Signals))
+	}
+	return s.Quit()
+}
+
+// waitGone waits up to d for the screen to end, and reports whether it did. It checks at least once.
+func (s Screen) waitGone(ctx context.Context, d time.Duration) (bool, error) {
+	deadline := time.Now().Add(d)
+	for {
+		if !s.isOnline() {
+			return true, nil
+		}
+
+		left := time.Until(deadline)
+		if left <= 0 {
+			return false, nil
+		}
+		if left > s.m().outputPoll() {
+			left = s.m().outputPoll()
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(left):
+		}
+	}
+}
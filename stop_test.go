@@ -0,0 +1,65 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// stubbornRunner plays a screen whose shell only dies of the given signal.
+type stubbornRunner struct {
+	*fakeRunner
+	diesOf string
+}
+
+func (r *stubbornRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "kill" && args[0] == r.diesOf {
+		r.outputs["screen -ls a+b"] = "No Sockets found in /run/screen/S-root.\n"
+	}
+	return r.fakeRunner.Run(ctx, name, args...)
+}
+
+func TestTerminate(t *testing.T) {
+	policy := StopPolicy{Signals: []syscall.Signal{syscall.SIGHUP, syscall.SIGKILL}, Waits: []time.Duration{time.Millisecond}}
+	for _, c := range []struct {
+		diesOf string
+		quit   bool
+		want   error
+		kills  int
+	}{
+		{"-1", false, nil, 1},
+		{"-9", false, nil, 2},
+		{"", false, ErrStillRunning, 2},
+		{"", true, nil, 2},
+	} {
+		r := &stubbornRunner{fakeRunner: &fakeRunner{outputs: map[string]string{
+			"screen -ls a+b":                       fakeList,
+			"ps --no-headers --ppid 4242 -o pid:1": "4300\n",
+			"ps --no-headers --ppid 4300 -o pid:1": "",
+			"kill -1 4300":                         "",
+			"kill -9 4300":                         "",
+			"/usr/bin/screen -S 4242.a+b -X quit":  "",
+		}}, diesOf: c.diesOf}
+		m := NewManagerWithRunner(r)
+		m.Defaults.OutputPoll = time.Millisecond
+		s, err := m.Get("a+b")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		policy.FinallyQuit = c.quit
+		if err = s.Terminate(context.Background(), policy); !errors.Is(err, c.want) {
+			t.Errorf("%+v: got %v", c, err)
+		}
+		ran := strings.Join(r.ran, "\n")
+		if kills := strings.Count(ran, "kill -"); kills != c.kills {
+			t.Errorf("%+v: sent %d signals", c, kills)
+		}
+		if quit := strings.Contains(ran, "-X quit"); quit != c.quit {
+			t.Errorf("%+v: quit is %v", c, quit)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package screen
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// Throughput is how much a screen has output, as seen by a Capture (or LogTo), so a stalled job can be told apart from
+// one that's working. Output isn't counted while nothing is capturing the screen.
+type Throughput struct {
+	Interval     time.Duration // Length of the interval Bytes and Lines were counted over, see Manager.ThroughputInterval
+	Bytes, Lines int64         // Output in the last complete interval
+
+	TotalBytes, TotalLines int64     // Output since the screen was first captured
+	LastOutput             time.Time // Zero if nothing was captured yet
+}
+
+// Throughput returns how much the screen has output recently. It's all zeros if the screen was never captured.
+func (s Screen) Throughput() Throughput {
+	v, ok := s.m().meters.Load(s.Name)
+	if !ok {
+		return Throughput{Interval: s.m().throughputInterval()}
+	}
+	return v.(*meter).read(time.Now(), s.m().throughputInterval())
+}
+
+// throughputInterval returns the Manager's ThroughputInterval, or its default.
+func (m *Manager) throughputInterval() time.Duration {
+	if m.ThroughputInterval <= 0 {
+		return time.Second * 10
+	}
+	return m.ThroughputInterval
+}
+
+// meter loads the output meter of the given screen name, creating it if needed.
+func (m *Manager) meter(name string) *meter {
+	v, _ := m.meters.LoadOrStore(name, new(meter))
+	return v.(*meter)
+}
+
+// meter counts a screen's output in fixed intervals.
+type meter struct {
+	mutex sync.Mutex
+
+	start                time.Time // Of the current interval
+	bytes, lines         int64     // In the current interval
+	prevBytes, prevLines int64     // In the last complete interval
+
+	totalBytes, totalLines int64
+	last                   time.Time
+}
+
+// add counts output seen at now.
+func (mt *meter) add(now time.Time, interval time.Duration, p []byte) {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	mt.roll(now, interval)
+	n, lines := int64(len(p)), int64(bytes.Count(p, []byte{'\n'}))
+	mt.bytes, mt.lines = mt.bytes+n, mt.lines+lines
+	mt.totalBytes, mt.totalLines = mt.totalBytes+n, mt.totalLines+lines
+	mt.last = now
+}
+
+// read returns what was counted, as of now.
+func (mt *meter) read(now time.Time, interval time.Duration) Throughput {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	mt.roll(now, interval)
+	return Throughput{
+		Interval:   interval,
+		Bytes:      mt.prevBytes,
+		Lines:      mt.prevLines,
+		TotalBytes: mt.totalBytes,
+		TotalLines: mt.totalLines,
+		LastOutput: mt.last,
+	}
+}
+
+// roll moves on to the interval now is in. Must be called with the mutex held.
+func (mt *meter) roll(now time.Time, interval time.Duration) {
+	if mt.start.IsZero() {
+		mt.start = now
+		return
+	}
+
+	elapsed := now.Sub(mt.start)
+	if elapsed < interval {
+		return
+	}
+
+	if elapsed < interval*2 {
+		mt.prevBytes, mt.prevLines = mt.bytes, mt.lines
+	} else { // A whole interval went by without any output
+		mt.prevBytes, mt.prevLines = 0, 0
+	}
+	mt.bytes, mt.lines = 0, 0
+	mt.start = mt.start.Add(elapsed - elapsed%interval)
+}
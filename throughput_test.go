@@ -0,0 +1,32 @@
+package screen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeter(t *testing.T) {
+	var mt meter
+	start, interval := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC), time.Second*10
+
+	mt.add(start, interval, []byte("one\ntwo\n"))
+	mt.add(start.Add(time.Second*5), interval, []byte("three"))
+	if got := mt.read(start.Add(time.Second*9), interval); got.Bytes != 0 || got.TotalBytes != 13 || got.TotalLines != 2 {
+		t.Errorf("got %+v before the first interval ended", got)
+	}
+
+	got := mt.read(start.Add(time.Second*12), interval)
+	if got.Bytes != 13 || got.Lines != 2 || !got.LastOutput.Equal(start.Add(time.Second*5)) {
+		t.Errorf("got %+v after the first interval", got)
+	}
+
+	mt.add(start.Add(time.Second*15), interval, []byte("\n"))
+	if got = mt.read(start.Add(time.Second*21), interval); got.Bytes != 1 || got.Lines != 1 {
+		t.Errorf("got %+v after the second interval", got)
+	}
+
+	// Stalled
+	if got = mt.read(start.Add(time.Minute), interval); got.Bytes != 0 || got.TotalBytes != 14 {
+		t.Errorf("got %+v after a minute without output", got)
+	}
+}
@@ -0,0 +1,117 @@
+package screen
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Kinds of transcript events.
+const (
+	TranscriptInput  = "input"  // Text stuffed into the screen
+	TranscriptOutput = "output" // Text the screen displayed
+)
+
+// TranscriptEvent is one line of a transcript written by a Recorder.
+type TranscriptEvent struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	Data string    `json:"data"`
+}
+
+// Recorder writes a transcript of a screen as JSON lines (one TranscriptEvent each): everything stuffed through the
+// Recorder, interleaved with everything the screen outputs, so the session can be reconstructed later.
+type Recorder struct {
+	s       Screen
+	capture *Capture
+
+	mu  sync.Mutex // Guards enc and err
+	enc *json.Encoder
+	err error
+
+	done chan struct{}
+}
+
+// Record starts recording the screen's transcript into w. Input only shows up if it's stuffed through the Recorder.
+// Output is captured like Capture does, so don't use Log or Capture until the Recorder is closed.
+func (s Screen) Record(w io.Writer) (*Recorder, error) {
+	c, err := s.Capture()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{s: s, capture: c, enc: json.NewEncoder(w), done: make(chan struct{})}
+	go r.readOutput()
+	return r, nil
+}
+
+// readOutput records the screen's output until the capture is closed.
+func (r *Recorder) readOutput() {
+	defer close(r.done)
+
+	buf := make([]byte, 4096)
+	var pending []byte // Incomplete UTF-8 sequence at the end of the last read
+	for {
+		n, err := r.capture.Read(buf)
+		if n > 0 {
+			var complete []byte
+			complete, pending = splitUTF8(append(pending, buf[:n]...))
+			if len(complete) > 0 {
+				r.write(TranscriptOutput, string(complete))
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Stuff stuffs text into the screen like Screen.Stuff, and records it.
+func (r *Recorder) Stuff(commands ...string) error {
+	if err := r.s.Stuff(commands...); err != nil {
+		return err
+	}
+
+	// Stuff joins its arguments with spaces
+	return r.write(TranscriptInput, strings.Join(commands, " "))
+}
+
+// write records an event. Once writing fails, every later write returns the same error.
+func (r *Recorder) write(kind, data string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.err = r.enc.Encode(TranscriptEvent{Time: time.Now(), Kind: kind, Data: data})
+	}
+	return r.err
+}
+
+// Close stops recording. It returns the first error writing the transcript, if there was one.
+func (r *Recorder) Close() error {
+	err := r.capture.Close()
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	return err
+}
+
+// splitUTF8 splits b before a trailing incomplete UTF-8 sequence, so text read in chunks isn't cut mid-character.
+func splitUTF8(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], append([]byte{}, b[i:]...)
+			}
+			break
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,26 @@
+package screen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitUTF8(t *testing.T) {
+	euro := []byte("€") // 3 bytes
+
+	tests := []struct {
+		in, complete, rest []byte
+	}{
+		{[]byte("abc"), []byte("abc"), nil},
+		{append([]byte("ab"), euro[:2]...), []byte("ab"), euro[:2]},
+		{append([]byte("ab"), euro...), append([]byte("ab"), euro...), nil},
+		{[]byte{0xff}, []byte{0xff}, nil}, // Invalid, but nothing to wait for
+	}
+
+	for _, test := range tests {
+		complete, rest := splitUTF8(test.in)
+		if !bytes.Equal(complete, test.complete) || !bytes.Equal(rest, test.rest) {
+			t.Errorf("splitUTF8(%q) = %q, %q, want %q, %q", test.in, complete, rest, test.complete, test.rest)
+		}
+	}
+}
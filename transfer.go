@@ -0,0 +1,193 @@
+package screen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch is returned by UploadFile and DownloadFile when the file that arrived differs from the one sent.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+const (
+	transferChunk = 2048 // Bytes per stuffed line, which stays below the 4095 characters a terminal line may have
+	transferSync  = 16   // Chunks stuffed before waiting for the shell to catch up
+)
+
+// UploadFile copies a local file to remotePath, on whatever host the shell in the screen's current window runs, by
+// typing it in as base64. This works over serial consoles and SSH sessions where scp isn't an option; the shell has
+// to be a POSIX one with base64 and sha256sum. The file is checked against its SHA-256 once it arrived, and
+// ErrChecksumMismatch is returned if it differs. Like Capture, this takes over the screen's logging while it runs.
+func (s Screen) UploadFile(ctx context.Context, localPath, remotePath string) error {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+
+	tmp := shellQuote(remotePath + ".b64")
+	lines := uploadLines(data, tmp)
+	for i, line := range lines {
+		if err = s.Stuff(line); err != nil {
+			return err
+		}
+		if (i+1)%transferSync == 0 {
+			if _, err = s.runDelimited(ctx, "true"); err != nil {
+				return err
+			}
+		}
+	}
+
+	out, err := s.runDelimited(ctx, "base64 -d "+tmp+" > "+shellQuote(remotePath)+"; rm -f "+tmp+"; sha256sum < "+shellQuote(remotePath))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if want := hex.EncodeToString(sum[:]); !strings.HasPrefix(strings.TrimSpace(out), want) {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// uploadLines returns the shell lines writing data, base64 encoded, into the file tmp (already quoted). They start
+// with a space, which keeps them out of the history of most shells.
+func uploadLines(data []byte, tmp string) []string {
+	lines := []string{" : > " + tmp + "\n"}
+	for len(data) > 0 {
+		n := transferChunk
+		if n > len(data) {
+			n = len(data)
+		}
+		lines = append(lines, " printf '%s\\n' '"+base64.StdEncoding.EncodeToString(data[:n])+"' >> "+tmp+"\n")
+		data = data[n:]
+	}
+	return lines
+}
+
+// DownloadFile copies remotePath, on whatever host the shell in the screen's current window runs, to w, by having the
+// shell print it as base64. It's the counterpart of UploadFile, with the same requirements; the file is checked
+// against the SHA-256 the shell computes for it. Like Capture, this takes over the screen's logging while it runs.
+func (s Screen) DownloadFile(ctx context.Context, remotePath string, w io.Writer) error {
+	quoted := shellQuote(remotePath)
+	out, err := s.runDelimited(ctx, "base64 < "+quoted+" && sha256sum < "+quoted)
+	if err != nil {
+		return err
+	}
+
+	data, err := decodeDownload(out)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// decodeDownload decodes the output of DownloadFile's command: the file as base64, followed by its SHA-256.
+func decodeDownload(out string) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return nil, errors.New(strings.TrimSpace(out)) // The shell's complaint, i.e. about a missing file
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.Join(lines[:len(lines)-1], ""))
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != fields[0] {
+		return nil, fmt.Errorf("%w: got %x, want %s", ErrChecksumMismatch, sum, fields[0])
+	}
+	return data, nil
+}
+
+// runDelimited runs a shell command in the screen's current window, and returns its output. The output is found
+// between two markers the command is wrapped with, in the screen's output as Capture sees it.
+func (s Screen) runDelimited(ctx context.Context, command string) (string, error) {
+	marker := "go-gnu-screen-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	begin, end := marker+"-begin", marker+"-end"
+
+	c, err := s.Capture()
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	// The typed command shows up on the screen too, so it mustn't contain the markers verbatim
+	if err = s.Stuff(" echo " + splitQuote(begin) + "; " + command + "; echo " + splitQuote(end) + "\n"); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		c.Close() // Unblocks Read
+	}()
+
+	var out strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, err := c.Read(buf)
+		seen := out.Len() - len(end)
+		out.Write(buf[:n])
+
+		// Only look at everything once the end marker shows up, output may be large
+		if seen < 0 {
+			seen = 0
+		}
+		if strings.Contains(out.String()[seen:], end) {
+			if text, ok := delimited(out.String(), begin, end); ok {
+				return text, nil
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", err
+		}
+	}
+}
+
+// ansiEscape matches the terminal control sequences shells print around command output, i.e. for bracketed paste.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]|\x1b[()][0-9A-Za-z]|\r`)
+
+// delimited returns the lines of out between the lines holding the begin and end markers, if both showed up yet.
+func delimited(out, begin, end string) (string, bool) {
+	i := strings.Index(out, begin+"\r\n")
+	if i < 0 {
+		if i = strings.Index(out, begin+"\n"); i < 0 {
+			return "", false
+		}
+	}
+	out = out[i+len(begin):]
+	j := strings.Index(out, end)
+	if j < 0 {
+		return "", false
+	}
+
+	text := ansiEscape.ReplaceAllString(out[:j], "")
+	text = strings.TrimPrefix(text, "\n")
+	if k := strings.LastIndexByte(text, '\n'); k >= 0 {
+		return text[:k+1], true
+	}
+	return "", true
+}
+
+// shellQuote quotes an argument for a POSIX shell.
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// splitQuote quotes a marker for a POSIX shell, splitting it in two, so the marker only shows up once it's echoed.
+func splitQuote(marker string) string {
+	return "'" + marker[:6] + "''" + marker[6:] + "'"
+}
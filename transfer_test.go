@@ -0,0 +1,78 @@
+package screen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadLines(t *testing.T) {
+	data := make([]byte, transferChunk*2+100)
+	rand.Read(data)
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "it's here")
+	tmp := shellQuote(remote + ".b64")
+
+	script := strings.Join(uploadLines(data, tmp), "") + "base64 -d " + tmp + " > " + shellQuote(remote) + "\n"
+	if out, err := exec.Command("sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("%s: %v", out, err)
+	}
+
+	got, err := os.ReadFile(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("uploaded file differs")
+	}
+}
+
+func TestDelimited(t *testing.T) {
+	const begin, end = "go-gnu-screen-x-begin", "go-gnu-screen-x-end"
+	out := "$  echo 'go-gn''u-screen-x-begin'; cat f; echo 'go-gn''u-screen-x-end'\r\n\x1b[?2004l\r" +
+		begin + "\r\nline one\r\nline two\r\n" + end + "\r\n\x1b[?2004h$ "
+
+	text, ok := delimited(out, begin, end)
+	if !ok || text != "line one\nline two\n" {
+		t.Errorf("got %q, %v", text, ok)
+	}
+
+	if _, ok = delimited(out[:strings.Index(out, end)], begin, end); ok {
+		t.Error("found output before the end marker")
+	}
+	if text, ok = delimited(begin+"\n"+end+"\n", begin, end); !ok || text != "" {
+		t.Errorf("got %q, %v for empty output", text, ok)
+	}
+}
+
+func TestDecodeDownload(t *testing.T) {
+	data := make([]byte, 5000)
+	rand.Read(data)
+	file := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("sh", "-c", "base64 < "+shellQuote(file)+" && sha256sum < "+shellQuote(file)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeDownload(string(out))
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("got %d bytes, %v", len(got), err)
+	}
+
+	// A line got lost on the way
+	lines := strings.SplitN(string(out), "\n", 2)
+	if _, err = decodeDownload(lines[1]); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("got %v, want ErrChecksumMismatch", err)
+	}
+	if _, err = decodeDownload("sh: can't open /nope: no such file\n"); err == nil || !strings.Contains(err.Error(), "/nope") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package screen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginMode is whether a screen's windows are logged in, meaning they have a utmp entry and show up in "who".
+type LoginMode int
+
+const (
+	LoginDefault LoginMode = iota // Whatever the screenrc (or screen's compiled-in default) says
+	LoginOn
+	LoginOff
+)
+
+// flags returns the command line flags for New.
+func (l LoginMode) flags() []string {
+	switch l {
+	case LoginOn:
+		return []string{"-l"}
+	case LoginOff:
+		return []string{"-ln"}
+	}
+	return nil
+}
+
+// SetLogin adds or removes the utmp entry of the screen's current window.
+func (s Screen) SetLogin(on bool) error {
+	return s.builtinTemplateArgs("login", onOff(on))
+}
+
+// SetDefaultLogin sets whether new windows of the screen get a utmp entry, like "deflogin".
+func (s Screen) SetDefaultLogin(on bool) error {
+	return s.builtinTemplateArgs("deflogin", onOff(on))
+}
+
+const utmpPath = "/var/run/utmp"
+
+// Layout of glibc's struct utmp on Linux, which is the same on all 64-bit and most 32-bit architectures.
+const (
+	utmpSize       = 384
+	utmpUserType   = 7 // USER_PROCESS
+	utmpLineOffset = 8
+	utmpUserOffset = 44
+	utmpHostOffset = 76
+	utmpTimeOffset = 340
+)
+
+// UtmpEntry is a login record of one of a screen's windows.
+type UtmpEntry struct {
+	Window int    // Window number, -1 if screen didn't record it
+	Line   string // Terminal, i.e. "pts/3"
+	User   string
+	Host   string // Screen writes "<hostname>:S.<window>" here
+	PID    int
+	Time   time.Time
+}
+
+// UtmpEntries returns the utmp records of the screen's windows, i.e. the ones "who" lists. Only windows with login
+// turned on (see SetLogin) have one.
+func (s Screen) UtmpEntries() ([]UtmpEntry, error) {
+	if s.Process == nil {
+		return nil, os.ErrNotExist
+	}
+
+	// Windows are direct children of the screen, with a terminal each
+	out, err := s.m().combined("ps", "--no-headers", "--ppid", strconv.Itoa(s.Process.Pid), "-o", "tty:1")
+	if err != nil && len(out) > 0 {
+		return nil, err
+	}
+	ttys := make(map[string]bool)
+	for _, tty := range strings.Fields(string(out)) {
+		ttys[tty] = true
+	}
+
+	b, err := s.m().readFile(utmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []UtmpEntry
+	for _, e := range parseUtmp(b) {
+		if ttys[e.Line] {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+// parseUtmp returns the user process records in a utmp file.
+func parseUtmp(b []byte) (res []UtmpEntry) {
+	for ; len(b) >= utmpSize; b = b[utmpSize:] {
+		if binary.LittleEndian.Uint16(b) != utmpUserType {
+			continue
+		}
+
+		e := UtmpEntry{
+			Window: -1,
+			Line:   cString(b[utmpLineOffset : utmpLineOffset+32]),
+			User:   cString(b[utmpUserOffset : utmpUserOffset+32]),
+			Host:   cString(b[utmpHostOffset : utmpHostOffset+256]),
+			PID:    int(int32(binary.LittleEndian.Uint32(b[4:]))),
+			Time:   time.Unix(int64(int32(binary.LittleEndian.Uint32(b[utmpTimeOffset:]))), 0),
+		}
+		if i := strings.LastIndex(e.Host, ":S."); i >= 0 {
+			if n, err := strconv.Atoi(e.Host[i+3:]); err == nil {
+				e.Window = n
+			}
+		}
+		res = append(res, e)
+	}
+	return
+}
+
+// cString converts a NUL padded C string.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,34 @@
+package screen
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseUtmp(t *testing.T) {
+	record := func(typ uint16, pid uint32, line, user, host string, sec uint32) []byte {
+		b := make([]byte, utmpSize)
+		binary.LittleEndian.PutUint16(b, typ)
+		binary.LittleEndian.PutUint32(b[4:], pid)
+		copy(b[utmpLineOffset:], line)
+		copy(b[utmpUserOffset:], user)
+		copy(b[utmpHostOffset:], host)
+		binary.LittleEndian.PutUint32(b[utmpTimeOffset:], sec)
+		return b
+	}
+
+	b := record(2, 0, "~", "reboot", "", 0) // BOOT_TIME, skipped
+	b = append(b, record(utmpUserType, 4242, "pts/3", "root", "box:S.1", 1700000000)...)
+	b = append(b, record(utmpUserType, 99, "pts/0", "alice", "10.0.0.1", 1700000000)...)
+
+	got := parseUtmp(b)
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	if e := got[0]; e.Window != 1 || e.Line != "pts/3" || e.User != "root" || e.PID != 4242 || e.Time.Unix() != 1700000000 {
+		t.Errorf("got %+v", e)
+	}
+	if got[1].Window != -1 {
+		t.Errorf("got window %d for a non-screen entry", got[1].Window)
+	}
+}
@@ -0,0 +1,33 @@
+package screen
+
+import (
+	"errors"
+
+	"github.com/Mexican-Man/go-gnu-screen/screenparse"
+)
+
+// Version is a version of screen, i.e. 4.9.0 for "Screen version 4.09.00".
+type Version = screenparse.Version
+
+// Version returns the version of screen on the Manager's host. It's only looked up once.
+func (m *Manager) Version() (Version, error) {
+	m.versionMutex.Lock()
+	defer m.versionMutex.Unlock()
+
+	if m.version != nil {
+		return *m.version, nil
+	}
+
+	// screen -v exits with 1
+	out, err := m.combined(screenExec, "-v")
+	v, parseErr := screenparse.ParseVersion(string(out))
+	if parseErr != nil {
+		if err = listError(out, err); errors.Is(err, ErrNotInstalled) {
+			return v, err
+		}
+		return v, parseErr
+	}
+
+	m.version = &v
+	return v, nil
+}
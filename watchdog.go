@@ -0,0 +1,100 @@
+package screen
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrHung is returned by CheckResponsive when the screen's shell didn't answer in time.
+var ErrHung = errors.New("screen is not responding")
+
+// CheckResponsive stuffs a harmless echo into the screen, and waits for its output to show up on the screen. If it
+// doesn't within timeout, ErrHung is returned. The screen's window has to be sitting at a shell prompt for this to
+// make sense.
+func (s Screen) CheckResponsive(ctx context.Context, timeout time.Duration) error {
+	marker := "go-gnu-screen-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	// The typed command shows up on the screen even if the shell is stuck, so it mustn't contain the marker verbatim
+	if err := s.Stuff("echo " + splitQuote(marker) + "\n"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		text, err := s.HardcopyString()
+		if err != nil {
+			return err
+		}
+		if strings.Contains(text, marker) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrHung
+			}
+			return ctx.Err()
+		case <-time.After(s.m().outputPoll()):
+		}
+	}
+}
+
+// Watchdog periodically checks that a screen's shell is responsive, see CheckResponsive.
+type Watchdog struct {
+	Interval time.Duration // Between checks, 1 minute if zero
+	Timeout  time.Duration // For each check, 10 seconds if zero
+	// OnHung, if set, is called every time a check fails, i.e. to signal, kill or recreate the screen.
+	OnHung func(s Screen)
+
+	hung int32
+}
+
+// Run checks the screen until ctx is done, or the screen can't be checked anymore (i.e. it's gone).
+func (w *Watchdog) Run(ctx context.Context, s Screen) error {
+	interval, timeout := w.Interval, w.Timeout
+	if interval == 0 {
+		interval = time.Minute
+	}
+	if timeout == 0 {
+		timeout = time.Second * 10
+	}
+
+	ctx, cancel, err := s.m().bind(ctx, s.Name)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for {
+		err := s.CheckResponsive(ctx, timeout)
+		switch {
+		case errors.Is(err, ErrHung):
+			atomic.StoreInt32(&w.hung, 1)
+			s.m().emit(Event{Type: EventHung, Screen: s})
+			if w.OnHung != nil {
+				w.OnHung(s)
+			}
+		case err != nil:
+			return err
+		default:
+			atomic.StoreInt32(&w.hung, 0)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Hung reports whether the last check failed.
+func (w *Watchdog) Hung() bool {
+	return atomic.LoadInt32(&w.hung) == 1
+}
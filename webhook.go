@@ -0,0 +1,105 @@
+package screen
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader is the header a Webhook puts the signature of its payload in: "sha256=" and the hex encoded
+// HMAC-SHA256 of the body, keyed with the Webhook's Secret.
+const SignatureHeader = "X-Screen-Signature-256"
+
+// Webhook POSTs events as JSON to a URL, i.e. to feed them into an alerting pipeline:
+//
+//	hook := &screen.Webhook{URL: "https://alerts.example.com/screen", Secret: secret}
+//	m.OnEvent = hook.Handle
+type Webhook struct {
+	URL    string
+	Secret string // If set, every request is signed, see SignatureHeader
+	// Retries is how often a failed delivery is retried, with exponential backoff starting at a second. 3 if zero,
+	// negative for none.
+	Retries int
+	Client  *http.Client // http.DefaultClient if nil
+	// OnError, if set, is called when Handle gives up on delivering an event.
+	OnError func(e Event, err error)
+}
+
+// Handle delivers an event in the background. It can be used as a Manager's OnEvent.
+func (w *Webhook) Handle(e Event) {
+	go func() {
+		if err := w.Notify(context.Background(), e); err != nil && w.OnError != nil {
+			w.OnError(e, err)
+		}
+	}()
+}
+
+// Notify delivers an event, retrying on network errors and 5xx responses.
+func (w *Webhook) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	retries := w.Retries
+	if retries == 0 {
+		retries = 3
+	}
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		if retry, err = w.post(ctx, body); err == nil || !retry || attempt >= retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a payload once. retry reports whether a failure might go away by trying again.
+func (w *Webhook) post(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests,
+			errors.New("webhook returned " + strconv.Itoa(res.StatusCode))
+	}
+	return false, nil
+}
+
+// Sign returns the signature of a webhook payload, as sent in SignatureHeader. Receivers can compare it to the
+// header with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
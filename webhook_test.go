@@ -0,0 +1,54 @@
+package screen
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestWebhook(t *testing.T) {
+	attempts := 0
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("s3cret", body) {
+			t.Error("bad signature")
+		}
+		var e struct {
+			Type   EventType
+			Screen struct{ Name string }
+		}
+		json.Unmarshal(body, &e)
+		got.Type, got.Screen.Name = e.Type, e.Screen.Name
+	}))
+	defer srv.Close()
+
+	hook := &Webhook{URL: srv.URL, Secret: "s3cret"}
+	e := Event{Type: EventDied, Screen: Screen{Name: "build", Process: &os.Process{Pid: 7}}}
+	if err := hook.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || got.Type != EventDied || got.Screen.Name != "build" {
+		t.Errorf("got %d attempts, event %+v", attempts, got)
+	}
+
+	// Client errors aren't retried
+	attempts = 0
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.NotFound(w, r)
+	})
+	if err := hook.Notify(context.Background(), e); err == nil || attempts != 1 {
+		t.Errorf("got %v after %d attempts, want an error after 1", err, attempts)
+	}
+}
@@ -0,0 +1,154 @@
+package screen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Window is one of a screen's windows.
+type Window struct {
+	Number int
+	Screen Screen
+}
+
+// Window returns the screen's window with the given number. The window isn't checked to exist.
+func (s Screen) Window(number int) Window {
+	return Window{Number: number, Screen: s}
+}
+
+// PID finds the process running in the window, the shell in most cases. Screen gives every process it starts the
+// window number in $WINDOW, and the oldest such child is the one the window was made with; later ones were started
+// with Exec.
+func (w Window) PID() (int, error) {
+	if w.Screen.Process == nil {
+		return 0, os.ErrNotExist
+	}
+
+	m := w.Screen.m()
+	children, err := m.childPIDs(w.Screen.Process.Pid)
+	if err != nil {
+		return 0, err
+	}
+
+	marker := []byte("WINDOW=" + strconv.Itoa(w.Number))
+	found, oldest := 0, uint64(0)
+	for _, child := range children {
+		environ, err := m.readFile("/proc/" + strconv.Itoa(child) + "/environ")
+		if err != nil {
+			continue // Gone, or not ours to read
+		}
+
+		for _, kv := range bytes.Split(environ, []byte{0}) {
+			if !bytes.Equal(kv, marker) {
+				continue
+			}
+			if started, err := m.procStartTime(child); err == nil && (found == 0 || started < oldest) {
+				found, oldest = child, started
+			}
+			break
+		}
+	}
+
+	if found == 0 {
+		return 0, os.ErrNotExist
+	}
+	return found, nil
+}
+
+// KillProcesses signals the process running in the window and everything below it, leaving the screen's other
+// windows alone.
+func (w Window) KillProcesses(signal syscall.Signal) error {
+	if err := w.Screen.checkProcess(); err != nil {
+		return err
+	}
+	if err := w.Screen.m().Policy.checkSignal(w.Screen.target()); err != nil {
+		return err
+	}
+	pid, err := w.PID()
+	if err != nil {
+		return err
+	}
+
+	// Collect the whole tree first, children may get reparented once their parent dies
+	pids, err := w.Screen.m().descendants(pid)
+	if err != nil {
+		return err
+	}
+	return w.Screen.m().signal(w.Screen.target(), signal, append([]int{pid}, pids...)...)
+}
+
+// ShellPID returns the PID of the process running in the screen's first window, usually the shell it was made with.
+// Unlike Process, which is screen itself, this is where the actual work happens.
+func (s Screen) ShellPID() (int, error) {
+	return s.Window(0).PID()
+}
+
+// SetEncoding sets the encoding of the window, i.e. "SJIS" or "ISO8859-1" for a legacy tool, so screen translates its
+// output correctly while the screen's other windows stay UTF-8. See "encoding" in "man screen" for the supported ones.
+func (w Window) SetEncoding(enc string) error {
+	if err := validateEncoding(enc); err != nil {
+		return err
+	}
+	return w.builtin("encoding", enc)
+}
+
+// SetDefaultEncoding sets the encoding of windows the screen makes from now on, like "defencoding".
+func (s Screen) SetDefaultEncoding(enc string) error {
+	if err := validateEncoding(enc); err != nil {
+		return err
+	}
+	return s.builtinTemplateArgs("defencoding", enc)
+}
+
+// SetHardstatus sets the window's hardstatus, i.e. "building 42%" or "idle", so whoever attaches sees what the
+// automation in it is up to. It shows where screen's hardstatus line or caption has "%h", like the default one does.
+// The text is shown as it is, "%" included.
+func (w Window) SetHardstatus(text string) error {
+	return w.builtin("hstatus", strings.ReplaceAll(text, "%", "%%"))
+}
+
+// SetDefaultHardstatus sets the hardstatus windows the screen makes from now on start with, like "defhstatus". Unlike
+// SetHardstatus, format may use screen's string escapes, i.e. "job %n: %t".
+func (s Screen) SetDefaultHardstatus(format string) error {
+	return s.builtinTemplateArgs("defhstatus", format)
+}
+
+// SetBCE sets whether the window erases with the current background color, like "bce". Full-screen programs that paint
+// colored areas, i.e. htop or a colored vim, expect it, and hardcopies or a web terminal show garbled blocks without it.
+func (w Window) SetBCE(on bool) error {
+	return w.builtin("bce", onOff(on))
+}
+
+// SetDefaultBCE sets whether windows the screen makes from now on erase with the background color, like "defbce".
+func (s Screen) SetDefaultBCE(on bool) error {
+	return s.builtinTemplateArgs("defbce", onOff(on))
+}
+
+// validateEncoding rejects encodings that can't be a name screen knows.
+func validateEncoding(enc string) error {
+	if enc == "" || strings.ContainsAny(enc, " \t\n'\"") {
+		return &os.SyscallError{Syscall: os.ErrInvalid.Error(), Err: errors.New("invalid encoding " + strconv.Quote(enc))}
+	}
+	return nil
+}
+
+// builtin runs a screen command in the window, like "screen -p <number> -X".
+func (w Window) builtin(command string, args ...string) error {
+	s := w.Screen
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	if err := s.verify(); err != nil {
+		return err
+	}
+
+	params := append([]string{"-S", s.target(), "-p", strconv.Itoa(w.Number), "-X", command}, args...)
+	if _, err := s.m().combined(screenExec, params...); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package screen
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeWindow starts a child of the test process that looks like the process of a screen window.
+func fakeWindow(t *testing.T, number string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", "sleep 30 & wait")
+	cmd.Env = append(os.Environ(), "WINDOW="+number)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	time.Sleep(time.Millisecond * 50) // Let sh fork sleep
+	return cmd
+}
+
+func TestWindowKillProcesses(t *testing.T) {
+	s := Screen{Name: "test", Process: &os.Process{Pid: os.Getpid()}}
+	zero, one := fakeWindow(t, "0"), fakeWindow(t, "1")
+
+	if pid, err := s.Window(1).PID(); err != nil || pid != one.Process.Pid {
+		t.Fatalf("got window 1 PID %d (%v), want %d", pid, err, one.Process.Pid)
+	}
+
+	if pid, err := s.ShellPID(); err != nil || pid != zero.Process.Pid {
+		t.Fatalf("got shell PID %d (%v), want %d", pid, err, zero.Process.Pid)
+	}
+
+	if err := s.Window(1).KillProcesses(syscall.SIGKILL); err != nil {
+		t.Fatal(err)
+	}
+	if err := one.Wait(); err == nil {
+		t.Error("window 1 is still running")
+	}
+	if err := zero.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Errorf("window 0 was killed too: %v", err)
+	}
+}
+
+func TestWindowSetEncoding(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b": fakeList,
+		"/usr/bin/screen -S 4242.a+b -p 2 -X encoding SJIS": "",
+	}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Window(2).SetEncoding("SJIS"); err != nil {
+		t.Error(err)
+	}
+	if err = s.Window(2).SetEncoding("ISO 8859-1"); err == nil {
+		t.Error("expected an error for an encoding with a space")
+	}
+}
+
+func TestWindowSetHardstatus(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b": fakeList,
+		"/usr/bin/screen -S 4242.a+b -p 1 -X hstatus building 42%%": "",
+		"/usr/bin/screen -S 4242.a+b -X defhstatus job %n":          "",
+	}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Window(1).SetHardstatus("building 42%"); err != nil {
+		t.Error(err)
+	}
+	if err = s.SetDefaultHardstatus("job %n"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWindowSetBCE(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{
+		"screen -ls a+b": fakeList,
+		"/usr/bin/screen -S 4242.a+b -p 0 -X bce on": "",
+		"/usr/bin/screen -S 4242.a+b -X defbce off":  "",
+	}}
+	s, err := NewManagerWithRunner(r).Get("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Window(0).SetBCE(true); err != nil {
+		t.Error(err)
+	}
+	if err = s.SetDefaultBCE(false); err != nil {
+		t.Error(err)
+	}
+}
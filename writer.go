@@ -0,0 +1,73 @@
+package screen
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle paces input written to a screen through a Writer, so slow consumers like serial links or remote shells
+// over a laggy connection don't drop what's typed into them.
+type Throttle struct {
+	BytesPerSecond int // 0 means unlimited
+	// Chunk is how many bytes are stuffed at once. If zero, it's a tenth of BytesPerSecond (at least 1), or
+	// everything passed to Write if BytesPerSecond is 0 too.
+	Chunk int
+}
+
+// Writer types everything written to it into a screen, as if it was passed to Stuff. It's safe for concurrent use,
+// writes don't interleave.
+type Writer struct {
+	s        Screen
+	throttle Throttle
+
+	mutex sync.Mutex
+	next  time.Time // When the next chunk may be sent
+}
+
+// Writer returns an io.Writer that types into the screen, paced by t.
+func (s Screen) Writer(t Throttle) *Writer {
+	return &Writer{s: s, throttle: t}
+}
+
+// Write stuffs p into the screen, chunk by chunk, waiting between chunks as long as the throttle says. It returns how
+// many bytes were stuffed before an error.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	chunk := w.chunk(len(p))
+	for n < len(p) {
+		end := n + chunk
+		if end > len(p) {
+			end = len(p)
+		}
+
+		if wait := time.Until(w.next); wait > 0 {
+			time.Sleep(wait)
+		}
+		if err = w.s.Stuff(string(p[n:end])); err != nil {
+			return n, err
+		}
+
+		if w.throttle.BytesPerSecond > 0 {
+			w.next = time.Now().Add(time.Duration(end-n) * time.Second / time.Duration(w.throttle.BytesPerSecond))
+		}
+		n = end
+	}
+	return n, nil
+}
+
+// chunk returns how many bytes to stuff at once out of a write of n.
+func (w *Writer) chunk(n int) int {
+	switch {
+	case w.throttle.Chunk > 0:
+		return w.throttle.Chunk
+	case w.throttle.BytesPerSecond >= 10:
+		return w.throttle.BytesPerSecond / 10
+	case w.throttle.BytesPerSecond > 0:
+		return 1
+	case n > 0:
+		return n
+	}
+	return 1
+}
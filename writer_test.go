@@ -0,0 +1,48 @@
+package screen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriterThrottle(t *testing.T) {
+	r := &fakeRunner{outputs: map[string]string{"screen -ls slow": "\t7.slow\t(Detached)\n"}}
+	for _, chunk := range []string{"echo ", "hello", " worl", "d\n"} {
+		r.outputs[screenExec+" -S 7.slow -X stuff "+chunk] = ""
+	}
+	m := NewManagerWithRunner(r)
+	s, err := m.Get("slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	w := s.Writer(Throttle{BytesPerSecond: 100, Chunk: 5})
+	if n, err := w.Write([]byte("echo hello world\n")); err != nil || n != 17 {
+		t.Fatalf("wrote %d, %v", n, err)
+	}
+
+	// The first chunk goes out right away, the other three wait 50ms each
+	if elapsed := time.Since(start); elapsed < time.Millisecond*150 {
+		t.Errorf("took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestWriterChunk(t *testing.T) {
+	tests := []struct {
+		throttle Throttle
+		n, want  int
+	}{
+		{Throttle{}, 42, 42},
+		{Throttle{BytesPerSecond: 1000}, 42, 100},
+		{Throttle{BytesPerSecond: 3}, 42, 1},
+		{Throttle{BytesPerSecond: 1000, Chunk: 8}, 42, 8},
+	}
+
+	for _, test := range tests {
+		w := Writer{throttle: test.throttle}
+		if got := w.chunk(test.n); got != test.want {
+			t.Errorf("%+v: chunk(%d) = %d, want %d", test.throttle, test.n, got, test.want)
+		}
+	}
+}
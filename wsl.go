@@ -0,0 +1,15 @@
+package screen
+
+const wslExec = "wsl.exe"
+
+// NewWSLManager returns a Manager that runs every screen command inside a WSL distribution through "wsl.exe -d <distro>".
+// Commands are executed directly rather than through the distribution's shell, so arguments are never reinterpreted.
+// Leave distro empty to use the default distribution. Paths given to the Manager's screens (logs, hardcopies, etc.) are
+// paths inside the distribution, not Windows paths.
+func NewWSLManager(distro string) *Manager {
+	prefix := []string{wslExec}
+	if distro != "" {
+		prefix = append(prefix, "-d", distro)
+	}
+	return NewManagerWithRunner(ExecRunner{Prefix: append(prefix, "-e")})
+}
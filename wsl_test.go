@@ -0,0 +1,17 @@
+package screen
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWSLManagerCommand(t *testing.T) {
+	m := NewWSLManager("Ubuntu")
+	cmd, _ := m.command(context.Background(), screenExec, "-ls")
+
+	want := []string{"wsl.exe", "-d", "Ubuntu", "-e", screenExec, "-ls"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("got %q, want %q", cmd.Args, want)
+	}
+}